@@ -1,9 +1,7 @@
 package argo
 
 import (
-	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -20,12 +18,40 @@ const (
 	requiredAttribute   string = "required"
 	envAttribute        string = "env"
 	defaultAttribute    string = "default"
+	commandAttribute    string = "cmd"
+	separatorAttribute  string = "sep"
+	duplicatesAttribute string = "duplicates"
+	negatableAttribute  string = "negatable"
+	countAttribute      string = "count"
+	groupAttribute      string = "group"
+	orderAttribute      string = "order"
+	completeAttribute   string = "complete"
+	configAttribute     string = "config"
 
 	attributeSeparator      string = ","
 	attributeValueSeparator string = "="
+	mapEntrySeparator       string = "="
+	longValueSeparator      string = "="
+	negationPrefix          string = "no-"
+	completeCommand         string = "__complete"
+
+	defaultHelpShort string = "h"
+	defaultHelpLong  string = "help"
+	versionLong      string = "version"
+
+	duplicatesError string = "error"
+	duplicatesLast  string = "last"
 )
 
+var separatorNames = map[string]string{
+	"comma": ",",
+	"space": " ",
+}
+
 var (
+	ErrHelp                     = newArgoError("help requested")
+	ErrCompletion               = newArgoError("completion requested")
+	ErrVersion                  = newArgoError("version requested")
 	ErrNotPointerToStruct       = newArgoError("argument must be a pointer to a struct")
 	ErrAttributeMissingValue    = newArgoError("attribute missing value")
 	ErrUnknownAttribute         = newArgoError("unknown attribute")
@@ -43,20 +69,64 @@ var (
 	ErrPositionalNotSet         = newArgoError("positional argument not set")
 	ErrFieldNotExported         = newArgoError("field must be exported")
 	ErrCouldNotSet              = newArgoError("could not set value")
+	ErrInvalidCommand           = newArgoError("command must be a pointer to a struct and can only have a help attribute")
+	ErrDuplicateCommandName     = newArgoError("duplicate command name")
+	ErrPositionalWithCommands   = newArgoError("positional arguments cannot be used together with commands")
+	ErrUnknownCommand           = newArgoError("unknown command")
+	ErrVariadicNotLast          = newArgoError("positional slice must be the last positional argument")
+	ErrMalformedMapEntry        = newArgoError("map entry must be in the key=value format")
+	ErrDuplicateMapKey          = newArgoError("duplicate map key")
+	ErrMissingValue             = newArgoError("argument missing value")
+	ErrInvalidNegatable         = newArgoError("negatable argument must be a bool with a long flag")
+	ErrInvalidCounter           = newArgoError("count argument must be an integer flag")
+	ErrUnknownEnv               = newArgoError("unknown environment variable")
+	ErrUnsupportedShell         = newArgoError("unsupported shell")
+	ErrUnknownCompletion        = newArgoError("unknown completion function")
+	ErrInvalidConfig            = newArgoError("invalid config file")
+	ErrUnknownConfigKey         = newArgoError("unknown config key")
+	ErrInvalidConfigValue       = newArgoError("config value has an unsupported type")
 )
 
 type arg struct {
-	name         string
-	short        string
-	long         string
-	env          string
-	isPositional bool
-	isRequired   bool
-	isFlag       bool
-	help         string
-	defaultValue string
-	setter       func(string) error
-	wasSet       bool
+	name             string
+	short            string
+	long             string
+	env              string
+	isPositional     bool
+	isRequired       bool
+	isFlag           bool
+	help             string
+	defaultValue     string
+	setter           func(string) error
+	wasSet           bool
+	command          string
+	isRepeated       bool
+	separator        string
+	isMap            bool
+	rejectDuplicates bool
+	isNegatable      bool
+	isCounter        bool
+	increment        func() error
+	counted          int
+	group            string
+	order            int
+	hasOrder         bool
+	metavar          string
+	kind             reflect.Kind
+	isGroup          bool
+	completion       string
+	completer        CompletionFunc
+	configKey        string
+	configName       string
+	configValue      interface{}
+}
+
+type command struct {
+	name     string
+	help     string
+	field    reflect.Value
+	value    reflect.Value
+	registry *argsRegistry
 }
 
 type argoError struct {
@@ -72,41 +142,24 @@ func newArgoError(msg string) *argoError {
 }
 
 type argsRegistry struct {
-	short      map[string]*arg
-	long       map[string]*arg
-	env        map[string]*arg
-	positional []*arg
-}
-
-func (r *argsRegistry) asRange() <-chan *arg {
-	ch := make(chan *arg)
-	go func() {
-		for _, argument := range r.short {
-			ch <- argument
-		}
-		for _, argument := range r.long {
-			ch <- argument
-		}
-		for _, argument := range r.positional {
-			ch <- argument
-		}
-		for _, argument := range r.env {
-			ch <- argument
-		}
-		close(ch)
-	}()
-	return ch
-}
-
-func (r *argsRegistry) deduplicated() map[*arg]struct{} {
-	dedup := make(map[*arg]struct{})
-	for argument := range r.asRange() {
-		dedup[argument] = struct{}{}
-	}
-	return dedup
+	short        map[string]*arg
+	long         map[string]*arg
+	env          map[string]*arg
+	positional   []*arg
+	commands     map[string]*command
+	args         []*arg
+	commandList  []*command
+	groups       map[string]string
+	parent       *argsRegistry
+	name         string
+	selected     *command
+	parser       *Parser
+	errors       []error
+	configPath   string
+	configGroups map[string]string
 }
 
-func interfaceToArgsRegistry(input interface{}) (*argsRegistry, error) {
+func (p *Parser) interfaceToArgsRegistry(input interface{}) (*argsRegistry, error) {
 	outputValue := reflect.ValueOf(input)
 
 	if outputValue.Kind() != reflect.Ptr || outputValue.IsNil() {
@@ -118,101 +171,69 @@ func interfaceToArgsRegistry(input interface{}) (*argsRegistry, error) {
 		return nil, ErrNotPointerToStruct
 	}
 
-	return newArgsRegistry(elem)
-}
-
-func Parse(input interface{}) error {
-	argumentsRegistry, err := interfaceToArgsRegistry(input)
-	if err != nil {
-		return err
-	}
-
-	if err = argumentsRegistry.parseInput(); err != nil {
-		return err
+	if len(p.helpShort) > 1 {
+		return nil, ErrShortNotSingleChar
 	}
 
-	return validateArgsRegistry(argumentsRegistry)
+	return p.newArgsRegistry(elem)
 }
 
-func PrintHelp(input interface{}) error {
-	argumentsRegistry, err := interfaceToArgsRegistry(input)
-	if err != nil {
-		return err
-	}
-	return argumentsRegistry.printHelp()
+func Parse(input interface{}) error {
+	return defaultParser.Parse(input)
 }
 
-func (r *argsRegistry) printHelp() error {
-	flags := make([]string, 0)
-	positionals := make([]string, 0)
-	envs := make([]string, 0)
-
-	for argument := range r.deduplicated() {
-		if argument.isPositional {
-			positionals = append(positionals, fmt.Sprintf("<%s>", argument.name))
-			continue
-		}
-
-		flag := ""
-		if argument.short != "" {
-			flag += fmt.Sprintf("-%s", argument.short)
-		}
-
-		if argument.long != "" {
-			if flag != "" {
-				flag += ", "
-			}
-			flag += fmt.Sprintf("--%s", argument.long)
-		}
-
-		hasFlag := flag != ""
-		if argument.env != "" {
-			if hasFlag {
-				flag += " "
-			}
-			flag += fmt.Sprintf("[ENV: %s]", argument.env)
-		}
-
-		if argument.help != "" {
-			flag += fmt.Sprintf(" - %s", argument.help)
-		}
+func ParseArgs(input interface{}, args []string, opts ...Option) error {
+	return defaultParser.with(opts).ParseArgs(input, args)
+}
 
-		if argument.defaultValue != "" {
-			flag += fmt.Sprintf(" (default: %s)", argument.defaultValue)
-		}
+func PrintHelp(input interface{}, opts ...Option) error {
+	return defaultParser.with(opts).PrintHelp(input)
+}
 
-		if argument.isRequired {
-			flag += " (REQUIRED)"
-		}
+func Usage(input interface{}, opts ...Option) string {
+	return defaultParser.with(opts).Usage(input)
+}
 
-		if hasFlag {
-			flags = append(flags, flag)
-		} else {
-			envs = append(envs, flag)
-		}
+func (r *argsRegistry) root() *argsRegistry {
+	root := r
+	for root.parent != nil {
+		root = root.parent
 	}
+	return root
+}
 
-	output := fmt.Sprintf("Usage: ./%s [flags] %s\n", os.Args[0], strings.Join(positionals, " "))
-
-	flags = append(flags, " -h, --help - Print this help message")
-	output += "\nFlags:\n"
-	for _, flag := range flags {
-		output += fmt.Sprintf("  %s\n", flag)
+func (r *argsRegistry) active() *argsRegistry {
+	active := r
+	for active.selected != nil {
+		active = active.selected.registry
 	}
+	return active
+}
 
-	if len(envs) > 0 {
-		output += "\nEnvironment variables:\n"
-		for _, env := range envs {
-			output += fmt.Sprintf("  %s\n", env)
-		}
+func (r *argsRegistry) fail(err error) error {
+	if !r.parser.allErrors {
+		return err
 	}
+	root := r.root()
+	root.errors = append(root.errors, err)
+	return nil
+}
 
-	return errors.New(output)
+func (r *argsRegistry) collectedErrors() error {
+	root := r.root()
+	if len(root.errors) == 0 {
+		return nil
+	}
+	return &ParseErrors{Errors: root.errors, usage: root.active().usage()}
 }
 
-func (r *argsRegistry) parseInput() error {
-	args := os.Args[1:]
+func (r *argsRegistry) parseInput(args []string) error {
+	if r.parent == nil && len(args) > 0 && args[0] == completeCommand {
+		return r.complete(args[1:])
+	}
+
 	positionalIndex := 0
+	positionalCount := 0
 	explicitPositional := false
 	for i := 0; i < len(args); i++ {
 		argText := args[i]
@@ -222,159 +243,433 @@ func (r *argsRegistry) parseInput() error {
 			continue
 		}
 
-		if argText == "-h" || argText == "--help" {
-			return r.printHelp()
+		if r.parser.isHelpFlag(argText) && !explicitPositional {
+			if err := r.printHelp(); err != nil {
+				return err
+			}
+			return ErrHelp
 		}
 
-		if strings.HasPrefix(argText, "-") && !explicitPositional {
-			if positionalIndex != 0 {
-				return ErrPositionalNotAtEnd
+		if r.parser.hasVersion && argText == "--"+versionLong && !explicitPositional {
+			if err := r.printVersion(); err != nil {
+				return err
 			}
+			return ErrVersion
+		}
 
-			argName := argText[1:]
-			var argument *arg
+		if strings.HasPrefix(argText, "-") && !explicitPositional {
+			if positionalCount != 0 {
+				if err := r.fail(&ParseError{Err: ErrPositionalNotAtEnd, Name: argText, Source: SourceFlag}); err != nil {
+					return err
+				}
+			}
 
 			if strings.HasPrefix(argText, "--") {
-				argName = argText[2:]
-				argument = r.long[argName]
-			} else {
-				argument = r.short[argName]
-			}
+				argName, value, hasValue := strings.Cut(argText[2:], longValueSeparator)
+				name := "--" + argName
+				if r.parser.configFlag != "" && argName == r.parser.configFlag {
+					if !hasValue {
+						if i+1 >= len(args) {
+							if err := r.fail(&ParseError{Err: ErrMissingValue, Name: name, Source: SourceFlag}); err != nil {
+								return err
+							}
+							continue
+						}
+						i++
+						value = args[i]
+					}
+					r.root().configPath = value
+					continue
+				}
 
-			if argument == nil {
-				return ErrUnknownArgumentName
+				argument := r.lookupLong(argName)
+				if argument == nil {
+					err := &ParseError{Err: ErrUnknownArgumentName, Name: name, Source: SourceFlag, Suggestions: r.suggestLong(argName)}
+					if err := r.fail(err); err != nil {
+						return err
+					}
+					continue
+				}
+
+				if !hasValue {
+					if argument.isFlag {
+						if err := argument.setFlag(); err != nil {
+							if err := r.fail(newSetError(argument, name, "", SourceFlag, err)); err != nil {
+								return err
+							}
+						}
+						continue
+					}
+					if i+1 >= len(args) {
+						if err := r.fail(&ParseError{Err: ErrMissingValue, Field: argument.name, Name: name, Source: SourceFlag}); err != nil {
+							return err
+						}
+						continue
+					}
+					i++
+					value = args[i]
+				}
+
+				if err := argument.setter(value); err != nil {
+					if err := r.fail(newSetError(argument, name, value, SourceFlag, err)); err != nil {
+						return err
+					}
+				}
+				continue
 			}
 
-			if !argument.isFlag {
-				i++
-				err := argument.setter(args[i])
-				if err != nil {
-					return ErrCouldNotSet
+			cluster := argText[1:]
+			if cluster == "" {
+				if err := r.fail(&ParseError{Err: ErrUnknownArgumentName, Name: argText, Source: SourceFlag}); err != nil {
+					return err
 				}
-			} else {
-				_ = argument.setter("true")
+				continue
 			}
+			for j := 0; j < len(cluster); j++ {
+				name := "-" + cluster[j:j+1]
+				if r.parser.isHelpFlag(name) {
+					if err := r.printHelp(); err != nil {
+						return err
+					}
+					return ErrHelp
+				}
+
+				argument := r.lookupShort(cluster[j : j+1])
+				if argument == nil {
+					err := &ParseError{Err: ErrUnknownArgumentName, Name: name, Source: SourceFlag}
+					if len(cluster) > 1 {
+						err.Suggestions = r.suggestLong(cluster)
+					}
+					if err := r.fail(err); err != nil {
+						return err
+					}
+					break
+				}
+
+				if argument.isFlag {
+					if err := argument.setFlag(); err != nil {
+						if err := r.fail(newSetError(argument, name, "", SourceFlag, err)); err != nil {
+							return err
+						}
+					}
+					continue
+				}
 
+				value := cluster[j+1:]
+				if value == "" {
+					if i+1 >= len(args) {
+						if err := r.fail(&ParseError{Err: ErrMissingValue, Field: argument.name, Name: name, Source: SourceFlag}); err != nil {
+							return err
+						}
+						break
+					}
+					i++
+					value = args[i]
+				}
+				if err := argument.setter(value); err != nil {
+					if err := r.fail(newSetError(argument, name, value, SourceFlag, err)); err != nil {
+						return err
+					}
+				}
+				break
+			}
 			continue
 		}
 
+		if len(r.commands) > 0 && !explicitPositional {
+			cmd, ok := r.commands[argText]
+			if !ok {
+				return r.fail(&ParseError{Err: ErrUnknownCommand, Value: argText, Source: SourcePositional, Suggestions: r.suggestCommand(argText)})
+			}
+			cmd.field.Set(cmd.value)
+			r.selected = cmd
+			return cmd.registry.parseInput(args[i+1:])
+		}
+
 		if len(r.positional) == 0 || positionalIndex >= len(r.positional) {
-			return ErrUnexpectedArgument
+			if err := r.fail(&ParseError{Err: ErrUnexpectedArgument, Value: argText, Source: SourcePositional}); err != nil {
+				return err
+			}
+			continue
 		}
 
 		argument := r.positional[positionalIndex]
 		if err := argument.setter(argText); err != nil {
-			return ErrCouldNotSet
+			if err := r.fail(newSetError(argument, argument.displayName(), argText, SourcePositional, err)); err != nil {
+				return err
+			}
+		}
+		positionalCount++
+		if !argument.isRepeated {
+			positionalIndex++
 		}
-		positionalIndex++
 	}
 	return nil
 }
 
 func validateArgsRegistry(argumentsRegistry *argsRegistry) error {
-	for argument := range argumentsRegistry.deduplicated() {
+	for _, argument := range argumentsRegistry.args {
 		if argument.wasSet {
 			continue
 		}
 
-		if argument.isPositional {
-			if argument.defaultValue != "" {
-				if err := argument.setter(argument.defaultValue); err != nil {
-					return ErrCouldNotSet
-				}
-				continue
+		var err error
+		switch {
+		case argument.isPositional:
+			err = validatePositional(argument)
+		default:
+			err = validateFlag(argumentsRegistry, argument)
+			if err == nil {
+				err = argument.applyCount()
 			}
-			return ErrPositionalNotSet
 		}
-
-		if argument.env != "" {
-			envValue := os.Getenv(argument.env)
-			if envValue != "" {
-				if err := argument.setter(envValue); err != nil {
-					return ErrCouldNotSet
-				}
-				continue
+		if err != nil {
+			if err := argumentsRegistry.fail(err); err != nil {
+				return err
 			}
 		}
+	}
+
+	if argumentsRegistry.selected != nil {
+		return validateArgsRegistry(argumentsRegistry.selected.registry)
+	}
+	return nil
+}
+
+func validatePositional(argument *arg) error {
+	if argument.configName != "" {
+		return argument.setConfig()
+	}
+
+	if argument.defaultValue != "" {
+		if err := argument.setJoined(argument.defaultValue); err != nil {
+			return newSetError(argument, argument.displayName(), argument.defaultValue, SourceDefault, err)
+		}
+		return nil
+	}
+	if argument.isRepeated && !argument.isRequired {
+		return nil
+	}
+	return &ParseError{Err: ErrPositionalNotSet, Field: argument.name, Name: argument.displayName(), Source: SourcePositional}
+}
 
-		if argument.defaultValue != "" {
-			if err := argument.setter(argument.defaultValue); err != nil {
-				return ErrCouldNotSet
+func validateFlag(argumentsRegistry *argsRegistry, argument *arg) error {
+	if argument.env != "" {
+		envValue, _ := argumentsRegistry.parser.lookupEnv(argument.env)
+		if envValue != "" {
+			if err := argument.setJoined(envValue); err != nil {
+				return newSetError(argument, argument.env, envValue, SourceEnv, err)
 			}
-			continue
+			return nil
 		}
+	}
+
+	if argument.configName != "" {
+		return argument.setConfig()
+	}
 
-		if argument.isRequired {
-			return ErrRequiredNotSet
+	if argument.defaultValue != "" {
+		if err := argument.setJoined(argument.defaultValue); err != nil {
+			return newSetError(argument, argument.displayName(), argument.defaultValue, SourceDefault, err)
 		}
+		return nil
+	}
+
+	if argument.isRequired && argument.counted == 0 {
+		return &ParseError{Err: ErrRequiredNotSet, Field: argument.name, Name: argument.displayName()}
 	}
 	return nil
 }
 
-func newArgsRegistry(elem reflect.Value) (*argsRegistry, error) {
+func (p *Parser) newArgsRegistry(elem reflect.Value) (*argsRegistry, error) {
 	registeredArgs := &argsRegistry{
-		short:      make(map[string]*arg),
-		long:       make(map[string]*arg),
-		positional: make([]*arg, 0),
-		env:        make(map[string]*arg),
+		parser:       p,
+		short:        make(map[string]*arg),
+		long:         make(map[string]*arg),
+		positional:   make([]*arg, 0),
+		env:          make(map[string]*arg),
+		commands:     make(map[string]*command),
+		groups:       make(map[string]string),
+		configGroups: make(map[string]string),
 	}
 
-	hasDefaultedPositional := false
+	if err := registeredArgs.registerFields(elem, ""); err != nil {
+		return nil, err
+	}
+
+	if len(registeredArgs.commands) > 0 && len(registeredArgs.positional) > 0 {
+		return nil, ErrPositionalWithCommands
+	}
+	return registeredArgs, nil
+}
+
+func (r *argsRegistry) registerFields(elem reflect.Value, group string) error {
 	for i := 0; i < elem.NumField(); i++ {
 		value := elem.Field(i)
 		structField := elem.Type().Field(i)
 
 		if !structField.IsExported() {
-			return nil, ErrFieldNotExported
+			return &ParseError{Err: ErrFieldNotExported, Field: structField.Name}
 		}
 
 		if structField.Tag.Get(argoTag) == "" {
 			continue
 		}
 
-		argument, err := parseArgument(value, structField)
+		argument, err := parseArgument(value, structField, r.parser.setters)
 		if err != nil {
-			return nil, err
+			return &ParseError{Err: err, Field: structField.Name}
 		}
 
-		if argument.isPositional {
-			if hasDefaultedPositional {
-				return nil, ErrPositionalDefaultNotLast
+		if argument.group == "" {
+			argument.group = group
+		}
+
+		if argument.command != "" {
+			if err := r.registerCommand(argument, value); err != nil {
+				return err
 			}
+			continue
+		}
 
-			registeredArgs.positional = append(registeredArgs.positional, argument)
+		if argument.isGroup {
+			r.groups[argument.group] = argument.help
+			r.configGroups[argument.getConfigKey()] = argument.group
+			if err := r.registerFields(value, argument.group); err != nil {
+				return err
+			}
+			continue
+		}
 
-			if argument.defaultValue != "" {
-				hasDefaultedPositional = true
+		if argument.separator == "" {
+			argument.separator = r.parser.separator
+		}
+		if argument.completion != "" {
+			completer, ok := r.parser.completions[argument.completion]
+			if !ok {
+				return &ParseError{Err: ErrUnknownCompletion, Field: structField.Name, Name: argument.completion}
+			}
+			argument.completer = completer
+		}
+		r.args = append(r.args, argument)
+
+		if argument.isPositional {
+			hasDefaultedPositional, hasVariadicPositional := false, false
+			if len(r.positional) > 0 {
+				last := r.positional[len(r.positional)-1]
+				hasDefaultedPositional = last.defaultValue != ""
+				hasVariadicPositional = last.isRepeated
+			}
+			if hasDefaultedPositional {
+				return &ParseError{Err: ErrPositionalDefaultNotLast, Field: structField.Name}
 			}
+			if hasVariadicPositional {
+				return &ParseError{Err: ErrVariadicNotLast, Field: structField.Name}
+			}
+
+			r.positional = append(r.positional, argument)
 			continue
 		}
 
 		if argument.env != "" {
-			if _, ok := registeredArgs.env[argument.env]; ok {
-				return nil, ErrDuplicateFlagName
+			if _, ok := r.env[argument.env]; ok {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: argument.env}
 			}
-			registeredArgs.env[argument.env] = argument
+			r.env[argument.env] = argument
 		}
 
 		if argument.short != "" {
-			if _, ok := registeredArgs.short[argument.short]; ok {
-				return nil, ErrDuplicateFlagName
+			if _, ok := r.short[argument.short]; ok {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: "-" + argument.short}
+			}
+			r.short[argument.short] = argument
+		}
+
+		for _, builtin := range r.parser.builtinArgs() {
+			if argument.short != "" && argument.short == builtin.short {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: "-" + argument.short}
+			}
+			if argument.long != "" && argument.long == builtin.long {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: "--" + argument.long}
 			}
-			registeredArgs.short[argument.short] = argument
 		}
 
 		if argument.long != "" {
-			if _, ok := registeredArgs.long[argument.long]; ok {
-				return nil, ErrDuplicateFlagName
+			if _, ok := r.long[argument.long]; ok {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: "--" + argument.long}
+			}
+			r.long[argument.long] = argument
+		}
+
+		if argument.isNegatable {
+			negation := argument.negation()
+			if _, ok := r.long[negation.long]; ok {
+				return &ParseError{Err: ErrDuplicateFlagName, Field: structField.Name, Name: "--" + negation.long}
 			}
-			registeredArgs.long[argument.long] = argument
+			r.long[negation.long] = negation
 		}
 	}
-	return registeredArgs, nil
+	return nil
+}
+
+func (r *argsRegistry) registerCommand(argument *arg, field reflect.Value) error {
+	if _, ok := r.commands[argument.command]; ok {
+		return &ParseError{Err: ErrDuplicateCommandName, Field: argument.name, Name: argument.command}
+	}
+
+	value := field
+	if value.IsNil() {
+		value = reflect.New(field.Type().Elem())
+	}
+
+	registry, err := r.parser.newArgsRegistry(value.Elem())
+	if err != nil {
+		return err
+	}
+	registry.parent = r
+	registry.name = argument.command
+
+	cmd := &command{
+		name:     argument.command,
+		help:     argument.help,
+		field:    field,
+		value:    value,
+		registry: registry,
+	}
+	r.commands[argument.command] = cmd
+	r.commandList = append(r.commandList, cmd)
+	return nil
+}
+
+func (r *argsRegistry) path() string {
+	if r.parent == nil {
+		return ""
+	}
+	if parentPath := r.parent.path(); parentPath != "" {
+		return parentPath + " " + r.name
+	}
+	return r.name
+}
+
+func (r *argsRegistry) lookupShort(name string) *arg {
+	for registry := r; registry != nil; registry = registry.parent {
+		if argument, ok := registry.short[name]; ok {
+			return argument
+		}
+	}
+	return nil
+}
+
+func (r *argsRegistry) lookupLong(name string) *arg {
+	for registry := r; registry != nil; registry = registry.parent {
+		if argument, ok := registry.long[name]; ok {
+			return argument
+		}
+	}
+	return nil
 }
 
-func parseArgument(fieldValue reflect.Value, structField reflect.StructField) (*arg, error) {
+func parseArgument(fieldValue reflect.Value, structField reflect.StructField, setters map[reflect.Kind]setterFunc) (*arg, error) {
 	argument := &arg{
 		name: structField.Name,
 	}
@@ -386,57 +681,253 @@ func parseArgument(fieldValue reflect.Value, structField reflect.StructField) (*
 		}
 	}
 
+	hasOtherAttributes := argument.short != "" || argument.long != "" || argument.env != "" ||
+		argument.isPositional || argument.isRequired || argument.defaultValue != ""
+
+	if argument.command != "" {
+		isStructPtr := structField.Type.Kind() == reflect.Ptr && structField.Type.Elem().Kind() == reflect.Struct
+		if !isStructPtr || hasOtherAttributes {
+			return nil, ErrInvalidCommand
+		}
+		return argument, nil
+	}
+
+	if argument.group != "" && structField.Type.Kind() == reflect.Struct && !hasOtherAttributes {
+		argument.isGroup = true
+		return argument, nil
+	}
+
 	if argument.short == "" && argument.long == "" && !argument.isPositional && argument.env == "" {
 		fieldName := strings.ToLower(structField.Name)
 		argument.short = fieldName[:1]
 		argument.long = fieldName
 	}
 
-	if argument.short == "h" || argument.long == "help" {
-		return nil, ErrDuplicateFlagName
-	}
-
-	kind := structField.Type.Kind()
-	isPtr := kind == reflect.Ptr
+	fieldType := structField.Type
+	isPtr := fieldType.Kind() == reflect.Ptr
 	if isPtr {
-		kind = structField.Type.Elem().Kind()
+		fieldType = fieldType.Elem()
 	}
 
-	setter, ok := setters[kind]
+	elemType := fieldType
+	setter, ok := setters[fieldType.Kind()]
+	var keySetter setterFunc
+	if !ok && fieldType.Kind() == reflect.Slice {
+		elemType = fieldType.Elem()
+		setter, ok = setters[elemType.Kind()]
+		argument.isRepeated = true
+	} else if !ok && fieldType.Kind() == reflect.Map {
+		elemType = fieldType.Elem()
+		setter, ok = setters[elemType.Kind()]
+		if keySetter = setters[fieldType.Key().Kind()]; keySetter == nil {
+			ok = false
+		}
+		argument.isRepeated = true
+		argument.isMap = true
+	}
 	if !ok {
 		return nil, ErrUnsupportedType
 	}
 
+	if argument.rejectDuplicates && !argument.isMap {
+		return nil, ErrAttributeInvalidValue
+	}
+
+	if argument.isNegatable && (argument.long == "" || fieldType.Kind() != reflect.Bool) {
+		return nil, ErrInvalidNegatable
+	}
+
+	if argument.isCounter {
+		switch fieldType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, ErrInvalidCounter
+		}
+		if argument.isPositional {
+			return nil, ErrInvalidCounter
+		}
+		argument.isFlag = true
+	}
+
 	argument.setter = func(value string) error {
+		target := fieldValue
 		if isPtr {
-			if fieldValue.IsNil() {
-				fieldValue.Set(reflect.New(structField.Type.Elem()))
+			if target.IsNil() {
+				target.Set(reflect.New(fieldType))
 			}
-			fieldValue = fieldValue.Elem()
+			target = target.Elem()
+		}
+
+		if argument.isMap {
+			if !argument.wasSet {
+				target.Set(reflect.MakeMap(fieldType))
+			}
+			argument.wasSet = true
+
+			rawKey, rawValue, found := strings.Cut(value, mapEntrySeparator)
+			if !found {
+				return ErrMalformedMapEntry
+			}
+
+			key := reflect.New(fieldType.Key()).Elem()
+			if err := keySetter(rawKey, key); err != nil {
+				return err
+			}
+			if argument.rejectDuplicates && target.MapIndex(key).IsValid() {
+				return ErrDuplicateMapKey
+			}
+
+			elem := reflect.New(elemType).Elem()
+			if err := setter(rawValue, elem); err != nil {
+				return err
+			}
+			target.SetMapIndex(key, elem)
+			return nil
+		}
+
+		if argument.isRepeated {
+			if !argument.wasSet {
+				target.Set(reflect.MakeSlice(fieldType, 0, 1))
+			}
+			argument.wasSet = true
+
+			elem := reflect.New(elemType).Elem()
+			if err := setter(value, elem); err != nil {
+				return err
+			}
+			target.Set(reflect.Append(target, elem))
+			return nil
 		}
 
 		argument.wasSet = true
-		return setter(value, fieldValue)
+		return setter(value, target)
 	}
 
-	if kind == reflect.Bool {
+	argument.kind = elemType.Kind()
+	argument.metavar = metavar(elemType)
+	if argument.isMap {
+		argument.metavar = "<key=value>"
+	}
+
+	if elemType.Kind() == reflect.Bool && !argument.isMap {
 		argument.isFlag = true
 	}
 
+	if argument.isCounter {
+		argument.increment = func() error {
+			target := fieldValue
+			if isPtr {
+				if target.IsNil() {
+					return argument.setter("1")
+				}
+				target = target.Elem()
+			}
+			if target.CanInt() {
+				return argument.setter(strconv.FormatInt(target.Int()+1, 10))
+			}
+			return argument.setter(strconv.FormatUint(target.Uint()+1, 10))
+		}
+	}
+
 	return argument, nil
 }
 
-func attributeToKeyValue(attribute string) (string, string, error) {
-	attrParts := strings.Split(attribute, attributeValueSeparator)
-	if len(attrParts) != 1 && len(attrParts) != 2 {
-		return "", "", ErrMalformedAttribute
+func metavar(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "<string>"
+	case reflect.Bool:
+		return "<bool>"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "<int>"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "<uint>"
+	case reflect.Float32, reflect.Float64:
+		return "<float>"
+	default:
+		return "<value>"
+	}
+}
+
+func (a *arg) negation() *arg {
+	return &arg{
+		name:   a.name,
+		long:   negationPrefix + a.long,
+		isFlag: true,
+		setter: func(value string) error {
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			return a.setter(strconv.FormatBool(!boolValue))
+		},
 	}
+}
 
-	attrKey := attrParts[0]
-	if len(attrParts) == 1 {
-		return attrKey, "", nil
+func (a *arg) displayName() string {
+	switch {
+	case a.isPositional:
+		return fmt.Sprintf("<%s>", a.name)
+	case a.long != "":
+		return "--" + a.long
+	case a.short != "":
+		return "-" + a.short
+	default:
+		return a.env
 	}
-	return attrKey, attrParts[1], nil
+}
+
+func (a *arg) sortKey() string {
+	switch {
+	case a.long != "":
+		return a.long
+	case a.short != "":
+		return a.short
+	default:
+		return strings.ToLower(a.env)
+	}
+}
+
+func (a *arg) setFlag() error {
+	if !a.isCounter {
+		return a.setter("true")
+	}
+	if a.wasSet {
+		return a.increment()
+	}
+	a.counted++
+	return nil
+}
+
+func (a *arg) applyCount() error {
+	for ; a.counted > 0; a.counted-- {
+		if err := a.increment(); err != nil {
+			return newSetError(a, a.displayName(), "", SourceFlag, err)
+		}
+	}
+	return nil
+}
+
+func (a *arg) setJoined(value string) error {
+	if !a.isRepeated {
+		return a.setter(value)
+	}
+
+	for _, part := range strings.Split(value, a.separator) {
+		if err := a.setter(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func attributeToKeyValue(attribute string) (string, string, error) {
+	attrKey, attrValue, _ := strings.Cut(attribute, attributeValueSeparator)
+	if attrKey == "" {
+		return "", "", ErrMalformedAttribute
+	}
+	return attrKey, attrValue, nil
 }
 
 func validateIdentifier(value string) error {
@@ -501,8 +992,52 @@ func parseAttribute(fieldName string, attribute string, argument *arg) error {
 		return parseAttributeBool(attrValue, &argument.isPositional)
 	case requiredAttribute:
 		return parseAttributeBool(attrValue, &argument.isRequired)
+	case negatableAttribute:
+		return parseAttributeBool(attrValue, &argument.isNegatable)
+	case countAttribute:
+		return parseAttributeBool(attrValue, &argument.isCounter)
 	case envAttribute:
 		return parseAttributeIdentifier(attrValue, strings.ToUpper(fieldName), &argument.env)
+	case commandAttribute:
+		return parseAttributeIdentifier(attrValue, strings.ToLower(fieldName), &argument.command)
+	case separatorAttribute:
+		if attrValue == "" {
+			return ErrAttributeMissingValue
+		}
+		if separator, ok := separatorNames[attrValue]; ok {
+			attrValue = separator
+		}
+		argument.separator = attrValue
+	case duplicatesAttribute:
+		switch attrValue {
+		case duplicatesError:
+			argument.rejectDuplicates = true
+		case duplicatesLast:
+			argument.rejectDuplicates = false
+		case "":
+			return ErrAttributeMissingValue
+		default:
+			return ErrAttributeInvalidValue
+		}
+	case configAttribute:
+		return parseAttributeIdentifier(attrValue, strings.ToLower(fieldName), &argument.configKey)
+	case completeAttribute:
+		return parseAttributeIdentifier(attrValue, strings.ToLower(fieldName), &argument.completion)
+	case groupAttribute:
+		if attrValue == "" {
+			attrValue = fieldName
+		}
+		argument.group = attrValue
+	case orderAttribute:
+		if attrValue == "" {
+			return ErrAttributeMissingValue
+		}
+		order, err := strconv.Atoi(attrValue)
+		if err != nil {
+			return ErrAttributeInvalidValue
+		}
+		argument.order = order
+		argument.hasOrder = true
 	case helpAttribute:
 		if attrValue == "" {
 			return ErrAttributeMissingValue
@@ -577,12 +1112,7 @@ var setters = map[reflect.Kind]setterFunc{
 }
 
 func RegisterSetter(t interface{}, setter setterFunc) error {
-	kind := reflect.TypeOf(t).Kind()
-	if _, ok := setters[kind]; ok {
-		return ErrSetterAlreadyExists
-	}
-	setters[kind] = setter
-	return nil
+	return defaultParser.RegisterSetter(t, setter)
 }
 
 func setterInt(value string, out reflect.Value, bitSize int) error {
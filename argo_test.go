@@ -1,8 +1,11 @@
 package argo
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -179,7 +182,7 @@ func TestParseField(t *testing.T) {
 	fieldValue := data.Elem().Field(0)
 	structField := data.Elem().Type().Field(0)
 
-	attribs, err := parseArgument(fieldValue, structField)
+	attribs, err := parseArgument(fieldValue, structField, setters)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -208,7 +211,7 @@ func TestParseField(t *testing.T) {
 	fieldValue = data.Elem().Field(1)
 	structField = data.Elem().Type().Field(1)
 
-	attribs, err = parseArgument(fieldValue, structField)
+	attribs, err = parseArgument(fieldValue, structField, setters)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -952,24 +955,38 @@ type argsHelp struct {
 }
 
 func TestHelp(t *testing.T) {
-	os.Args = []string{"test", "--help"}
+	output := &bytes.Buffer{}
 	args := argsHelp{}
-	if err := Parse(&args); err == nil {
-		t.Fatal("expected error")
+	if err := ParseArgs(&args, []string{"--help"}, WithProgramName("test"), WithOutput(output)); !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "Usage: ./test") {
+		t.Fatal("expected help text")
 	}
 
+	output.Reset()
 	args = argsHelp{}
-	if err := PrintHelp(&args); err == nil {
-		t.Fatal("expected error")
-	} else if !strings.Contains(err.Error(), "Usage: ./test") {
+	if err := PrintHelp(&args, WithProgramName("test"), WithOutput(output)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "Usage: ./test") {
+		t.Fatal("expected help text")
+	}
+
+	output.Reset()
+	if usage := Usage(&args, WithProgramName("test"), WithOutput(output)); !strings.Contains(usage, "Usage: ./test") {
 		t.Fatal("expected help text")
 	}
+	if output.Len() != 0 {
+		t.Fatal("expected no output")
+	}
 
 	num := 1
 	if err := PrintHelp(&num); err == nil {
 		t.Fatal("expected error")
-	} else if strings.Contains(err.Error(), "Usage: ./test") {
-		t.Fatal("should have failed")
+	}
+	if usage := Usage(&num); usage != "" {
+		t.Fatalf("expected empty usage, got '%s'", usage)
 	}
 }
 
@@ -984,3 +1001,685 @@ func TestHelpConflict(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+type argsDeploy struct {
+	Env   string `argo:"short,long,required"`
+	Force bool   `argo:"short,long"`
+}
+
+type argsRollback struct {
+	ID int `argo:"positional"`
+}
+
+type argsCommands struct {
+	Verbose  bool          `argo:"short,long"`
+	Deploy   *argsDeploy   `argo:"cmd,help=Deploy the service"`
+	Rollback *argsRollback `argo:"cmd=rollback"`
+}
+
+func TestCommands(t *testing.T) {
+	os.Args = []string{"test", "-v", "deploy", "--env", "prod"}
+	args := argsCommands{}
+	if err := Parse(&args); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Verbose {
+		t.Fatal("expected 'Verbose' to be true")
+	}
+	if args.Deploy == nil {
+		t.Fatal("expected 'Deploy' to be selected")
+	}
+	if args.Deploy.Env != "prod" {
+		t.Fatalf("expected 'prod', got '%s'", args.Deploy.Env)
+	}
+	if args.Rollback != nil {
+		t.Fatal("expected 'Rollback' to be nil")
+	}
+
+	os.Args = []string{"test", "rollback", "-v", "42"}
+	args = argsCommands{}
+	if err := Parse(&args); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Verbose {
+		t.Fatal("expected inherited 'Verbose' to be true")
+	}
+	if args.Deploy != nil {
+		t.Fatal("expected 'Deploy' to be nil")
+	}
+	if args.Rollback == nil || args.Rollback.ID != 42 {
+		t.Fatal("expected 'Rollback' to be selected with ID 42")
+	}
+
+	os.Args = []string{"test"}
+	args = argsCommands{}
+	if err := Parse(&args); err != nil {
+		t.Fatal(err)
+	}
+	if args.Deploy != nil || args.Rollback != nil {
+		t.Fatal("expected no command to be selected")
+	}
+
+	os.Args = []string{"test", "deploy"}
+	args = argsCommands{}
+	if err := Parse(&args); err == nil {
+		t.Fatal("expected error")
+	}
+
+	os.Args = []string{"test", "destroy"}
+	args = argsCommands{}
+	if err := Parse(&args); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCommandHelp(t *testing.T) {
+	output := &bytes.Buffer{}
+	args := argsCommands{}
+	err := ParseArgs(&args, []string{"deploy", "--help"}, WithProgramName("test"), WithOutput(output))
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "Usage: ./test deploy [flags]") {
+		t.Fatalf("expected command usage, got '%s'", output.String())
+	}
+	if !strings.Contains(output.String(), "--env") || !strings.Contains(output.String(), "Global flags:") {
+		t.Fatalf("expected command flags, got '%s'", output.String())
+	}
+
+	usage := Usage(&argsCommands{})
+	if !regexp.MustCompile(`deploy +Deploy the service`).MatchString(usage) {
+		t.Fatalf("expected command list, got '%s'", usage)
+	}
+}
+
+type argsCommandNotPointer struct {
+	Deploy argsDeploy `argo:"cmd"`
+}
+
+type argsCommandWithPositional struct {
+	Name   string      `argo:"positional"`
+	Deploy *argsDeploy `argo:"cmd"`
+}
+
+type argsCommandDuplicate struct {
+	A *argsDeploy `argo:"cmd=deploy"`
+	B *argsDeploy `argo:"cmd=deploy"`
+}
+
+func TestInvalidCommands(t *testing.T) {
+	os.Args = []string{"test"}
+	args := argsCommandNotPointer{}
+	if err := Parse(&args); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args2 := argsCommandWithPositional{}
+	if err := Parse(&args2); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args3 := argsCommandDuplicate{}
+	if err := Parse(&args3); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		address string
+		fail    bool
+	}{
+		{args: []string{}, address: ""},
+		{args: []string{"-a", "localhost"}, address: "localhost"},
+		{args: []string{"--addr", "127.0.0.1"}, address: "127.0.0.1"},
+		{args: []string{"--unknown", "value"}, fail: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			t.Parallel()
+			args := argsSimple{}
+			err := ParseArgs(&args, test.args)
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if args.Address != test.address {
+				t.Fatalf("expected '%s', got '%s'", test.address, args.Address)
+			}
+		})
+	}
+}
+
+func TestProgramName(t *testing.T) {
+	usage := Usage(&argsSimple{}, WithProgramName("tool"))
+	if !strings.Contains(usage, "Usage: ./tool [flags]") {
+		t.Fatalf("expected program name in usage, got '%s'", usage)
+	}
+}
+
+type argsSlices struct {
+	Tags    []string  `argo:"short,long"`
+	Include []string  `argo:"short=I"`
+	Ports   []int     `argo:"long,env=PORTS,default=80;443"`
+	Ratios  []float64 `argo:"long,sep=|,default=0.5|1.5"`
+	Files   []string  `argo:"positional"`
+}
+
+func TestSlices(t *testing.T) {
+	args := argsSlices{}
+	err := ParseArgs(&args, []string{"--tags", "a", "-t", "b", "-I", "dir1", "-I", "dir2", "x.go", "y.go"}, WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Tags, []string{"a", "b"}) {
+		t.Fatalf("expected '[a b]', got '%v'", args.Tags)
+	}
+	if !reflect.DeepEqual(args.Include, []string{"dir1", "dir2"}) {
+		t.Fatalf("expected '[dir1 dir2]', got '%v'", args.Include)
+	}
+	if !reflect.DeepEqual(args.Ports, []int{80, 443}) {
+		t.Fatalf("expected '[80 443]', got '%v'", args.Ports)
+	}
+	if !reflect.DeepEqual(args.Ratios, []float64{0.5, 1.5}) {
+		t.Fatalf("expected '[0.5 1.5]', got '%v'", args.Ratios)
+	}
+	if !reflect.DeepEqual(args.Files, []string{"x.go", "y.go"}) {
+		t.Fatalf("expected '[x.go y.go]', got '%v'", args.Files)
+	}
+
+	args = argsSlices{Tags: []string{"preset"}}
+	err = ParseArgs(&args, []string{"--ports", "8080", "-t", "c"}, WithEnvLookup(func(string) (string, bool) {
+		return "1;2", true
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Tags, []string{"c"}) {
+		t.Fatalf("expected '[c]', got '%v'", args.Tags)
+	}
+	if !reflect.DeepEqual(args.Ports, []int{8080}) {
+		t.Fatalf("expected '[8080]', got '%v'", args.Ports)
+	}
+	if args.Files != nil {
+		t.Fatalf("expected no files, got '%v'", args.Files)
+	}
+
+	args = argsSlices{}
+	err = ParseArgs(&args, []string{}, WithEnvLookup(func(string) (string, bool) {
+		return "1,2", true
+	}), WithSeparator(","))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Ports, []int{1, 2}) {
+		t.Fatalf("expected '[1 2]', got '%v'", args.Ports)
+	}
+
+	args = argsSlices{}
+	if err = ParseArgs(&args, []string{"--ports", "abc"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsSlices{}
+	if err = ParseArgs(&args, []string{"a.go", "--tags", "a"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsVariadicNotLast struct {
+	Files []string `argo:"positional"`
+	Dest  string   `argo:"positional"`
+}
+
+type argsVariadicRequired struct {
+	Files []string `argo:"positional,required"`
+}
+
+func TestVariadicPositional(t *testing.T) {
+	args := argsVariadicNotLast{}
+	if err := ParseArgs(&args, []string{"a", "b"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args2 := argsVariadicRequired{}
+	if err := ParseArgs(&args2, []string{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args2 = argsVariadicRequired{}
+	if err := ParseArgs(&args2, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type argsMaps struct {
+	Labels  map[string]string `argo:"short,long"`
+	Limits  map[string]int    `argo:"long,duplicates=error"`
+	Headers map[string]string `argo:"env=APP_HEADERS,default=a=1;b=2"`
+	Extra   map[string]string `argo:"env=APP_EXTRA,sep=comma"`
+}
+
+func TestMaps(t *testing.T) {
+	noEnv := WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	})
+
+	args := argsMaps{}
+	err := ParseArgs(&args, []string{"--labels", "team=infra", "-l", "tier=web", "-l", "tier=db", "--limits", "cpu=2"}, noEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Labels, map[string]string{"team": "infra", "tier": "db"}) {
+		t.Fatalf("expected labels, got '%v'", args.Labels)
+	}
+	if !reflect.DeepEqual(args.Limits, map[string]int{"cpu": 2}) {
+		t.Fatalf("expected limits, got '%v'", args.Limits)
+	}
+	if !reflect.DeepEqual(args.Headers, map[string]string{"a": "1", "b": "2"}) {
+		t.Fatalf("expected default headers, got '%v'", args.Headers)
+	}
+
+	args = argsMaps{}
+	err = ParseArgs(&args, []string{}, WithSeparator(","), WithEnvLookup(func(string) (string, bool) {
+		return "x=1,y=a=b", true
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Headers, map[string]string{"x": "1", "y": "a=b"}) {
+		t.Fatalf("expected env headers, got '%v'", args.Headers)
+	}
+
+	args = argsMaps{}
+	err = ParseArgs(&args, []string{}, WithEnvLookup(func(key string) (string, bool) {
+		if key == "APP_EXTRA" {
+			return "a=1,b=2", true
+		}
+		return "", false
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Extra, map[string]string{"a": "1", "b": "2"}) {
+		t.Fatalf("expected comma separated headers, got '%v'", args.Extra)
+	}
+	if !reflect.DeepEqual(args.Headers, map[string]string{"a": "1", "b": "2"}) {
+		t.Fatalf("expected default headers, got '%v'", args.Headers)
+	}
+
+	args = argsMaps{}
+	if err = ParseArgs(&args, []string{"--limits", "cpu=2", "--limits", "cpu=3"}, noEnv); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsMaps{}
+	if err = ParseArgs(&args, []string{"--limits", "cpu=abc"}, noEnv); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsMaps{}
+	if err = ParseArgs(&args, []string{"--labels", "team"}, noEnv); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseAttributesDuplicates(t *testing.T) {
+	attribs := &arg{}
+	if err := parseAttribute("name", "duplicates=error", attribs); err != nil {
+		t.Fatal(err)
+	}
+	if !attribs.rejectDuplicates {
+		t.Fatal("expected 'rejectDuplicates' to be true")
+	}
+
+	attribs = &arg{}
+	if err := parseAttribute("name", "duplicates=first", attribs); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsCluster struct {
+	Verbose bool   `argo:"short,long"`
+	Force   bool   `argo:"short,long"`
+	Port    int    `argo:"short,long"`
+	Output  string `argo:"short,long"`
+}
+
+func TestShortCluster(t *testing.T) {
+	args := argsCluster{}
+	if err := ParseArgs(&args, []string{"-vf", "-p8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Verbose || !args.Force {
+		t.Fatal("expected 'Verbose' and 'Force' to be true")
+	}
+	if args.Port != 8080 {
+		t.Fatalf("expected '8080', got '%d'", args.Port)
+	}
+
+	args = argsCluster{}
+	if err := ParseArgs(&args, []string{"-vfo", "out.txt", "-vp", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Verbose || !args.Force {
+		t.Fatal("expected 'Verbose' and 'Force' to be true")
+	}
+	if args.Output != "out.txt" {
+		t.Fatalf("expected 'out.txt', got '%s'", args.Output)
+	}
+	if args.Port != 1 {
+		t.Fatalf("expected '1', got '%d'", args.Port)
+	}
+
+	args = argsCluster{}
+	if err := ParseArgs(&args, []string{"-vofp"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.Force {
+		t.Fatal("expected 'Force' to be false")
+	}
+	if args.Output != "fp" {
+		t.Fatalf("expected 'fp', got '%s'", args.Output)
+	}
+
+	output := &bytes.Buffer{}
+	if err := ParseArgs(&argsCluster{}, []string{"-vh"}, WithOutput(output)); !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "Usage:") {
+		t.Fatalf("expected help, got '%s'", output.String())
+	}
+	if err := ParseArgs(&argsCluster{}, []string{"-voh"}, WithOutput(output)); err != nil {
+		t.Fatal(err)
+	}
+
+	args = argsCluster{}
+	if err := ParseArgs(&args, []string{"-vx"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsCluster{}
+	if err := ParseArgs(&args, []string{"-vp"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsCluster{}
+	if err := ParseArgs(&args, []string{"--port"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsLongValue struct {
+	Port    int               `argo:"long"`
+	Verbose bool              `argo:"short,long,default=true"`
+	Labels  map[string]string `argo:"long"`
+}
+
+func TestLongWithValue(t *testing.T) {
+	args := argsLongValue{}
+	if err := ParseArgs(&args, []string{"--port=8080", "--verbose=false", "--labels=team=infra"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.Port != 8080 {
+		t.Fatalf("expected '8080', got '%d'", args.Port)
+	}
+	if args.Verbose {
+		t.Fatal("expected 'Verbose' to be false")
+	}
+	if args.Labels["team"] != "infra" {
+		t.Fatalf("expected 'infra', got '%s'", args.Labels["team"])
+	}
+
+	args = argsLongValue{}
+	if err := ParseArgs(&args, []string{"--port", "8080", "--verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.Port != 8080 {
+		t.Fatalf("expected '8080', got '%d'", args.Port)
+	}
+	if !args.Verbose {
+		t.Fatal("expected 'Verbose' to be true")
+	}
+
+	args = argsLongValue{}
+	if err := ParseArgs(&args, []string{"--port="}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args = argsLongValue{}
+	if err := ParseArgs(&args, []string{"--verbose=maybe"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsNegatable struct {
+	Color   bool `argo:"short,long,negatable,default=true,help=Colorize output"`
+	Verbose bool `argo:"long,negatable"`
+}
+
+type argsNegatableNotBool struct {
+	Name string `argo:"long,negatable"`
+}
+
+type argsNegatableNoLong struct {
+	Color bool `argo:"short,negatable"`
+}
+
+func TestNegatable(t *testing.T) {
+	args := argsNegatable{}
+	if err := ParseArgs(&args, []string{}); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Color {
+		t.Fatal("expected 'Color' to be true")
+	}
+
+	args = argsNegatable{}
+	if err := ParseArgs(&args, []string{"--no-color", "--verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.Color {
+		t.Fatal("expected 'Color' to be false")
+	}
+	if !args.Verbose {
+		t.Fatal("expected 'Verbose' to be true")
+	}
+
+	args = argsNegatable{}
+	if err := ParseArgs(&args, []string{"--no-color=false", "--no-verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Color {
+		t.Fatal("expected 'Color' to be true")
+	}
+	if args.Verbose {
+		t.Fatal("expected 'Verbose' to be false")
+	}
+
+	usage := Usage(&argsNegatable{})
+	if !regexp.MustCompile(`-c, --color, --no-color +Colorize output`).MatchString(usage) {
+		t.Fatalf("expected negated flag in help, got '%s'", usage)
+	}
+
+	args2 := argsNegatableNotBool{}
+	if err := ParseArgs(&args2, []string{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	args3 := argsNegatableNoLong{}
+	if err := ParseArgs(&args3, []string{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsCounter struct {
+	Verbose int   `argo:"short,long,count,env=VERBOSITY"`
+	Level   uint8 `argo:"short=l,count,default=2"`
+	Force   bool  `argo:"short"`
+}
+
+type argsCounterInvalid struct {
+	Verbose string `argo:"short,count"`
+}
+
+func TestCounter(t *testing.T) {
+	noEnv := WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	})
+
+	args := argsCounter{}
+	if err := ParseArgs(&args, []string{"-vvv", "-fv", "--verbose"}, noEnv); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 5 {
+		t.Fatalf("expected '5', got '%d'", args.Verbose)
+	}
+	if args.Level != 2 {
+		t.Fatalf("expected '2', got '%d'", args.Level)
+	}
+
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"-l"}, WithEnvLookup(func(string) (string, bool) {
+		return "3", true
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 3 {
+		t.Fatalf("expected '3', got '%d'", args.Verbose)
+	}
+	if args.Level != 3 {
+		t.Fatalf("expected '3', got '%d'", args.Level)
+	}
+
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"-vv", "-l"}, WithEnvLookup(func(string) (string, bool) {
+		return "3", true
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 5 {
+		t.Fatalf("expected '5', got '%d'", args.Verbose)
+	}
+
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"--verbose=4"}, noEnv); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 4 {
+		t.Fatalf("expected '4', got '%d'", args.Verbose)
+	}
+
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"--verbose=3", "-v"}, noEnv); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 4 {
+		t.Fatalf("expected '4', got '%d'", args.Verbose)
+	}
+
+	config := WithConfigSource(ConfigMap{"verbose": 5, "level": 1})
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"-v", "-l"}, config, noEnv); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 6 || args.Level != 2 {
+		t.Fatalf("expected '6' and '2', got '%d' and '%d'", args.Verbose, args.Level)
+	}
+
+	args = argsCounter{}
+	if err := ParseArgs(&args, []string{"-v"}, config, WithEnvLookup(func(key string) (string, bool) {
+		return "2", key == "VERBOSITY"
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if args.Verbose != 3 {
+		t.Fatalf("expected '3', got '%d'", args.Verbose)
+	}
+
+	args2 := argsCounterInvalid{}
+	if err := ParseArgs(&args2, []string{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type argsHelpOrder struct {
+	Zeta    string `argo:"long"`
+	Alpha   string `argo:"long"`
+	Port    int    `argo:"long,group=Network"`
+	Verbose bool   `argo:"long,order=1"`
+	Host    string `argo:"long,group=Network,order=1"`
+	Debug   bool   `argo:"long,order=0"`
+	Mid     string `argo:"long"`
+}
+
+func assertOrder(t *testing.T, text string, parts ...string) {
+	t.Helper()
+	last := -1
+	for _, part := range parts {
+		index := strings.Index(text, part)
+		if index == -1 {
+			t.Fatalf("expected '%s' in '%s'", part, text)
+		}
+		if index < last {
+			t.Fatalf("expected '%s' to follow previous parts in '%s'", part, text)
+		}
+		last = index
+	}
+}
+
+func TestHelpOrder(t *testing.T) {
+	usage := Usage(&argsHelpOrder{})
+	for i := 0; i < 10; i++ {
+		if Usage(&argsHelpOrder{}) != usage {
+			t.Fatal("expected help to be deterministic")
+		}
+	}
+	assertOrder(t, usage, "--debug", "--verbose", "--zeta", "--alpha", "--mid", "--host", "--port")
+
+	usage = Usage(&argsHelpOrder{}, WithSortedHelp())
+	assertOrder(t, usage, "--debug", "--verbose", "--alpha", "--mid", "--zeta", "--host", "--port")
+}
+
+type argsCommandOrder struct {
+	Zeta  *argsDeploy `argo:"cmd"`
+	Alpha *argsDeploy `argo:"cmd"`
+}
+
+func TestHelpCommandOrder(t *testing.T) {
+	assertOrder(t, Usage(&argsCommandOrder{}), "zeta", "alpha")
+	assertOrder(t, Usage(&argsCommandOrder{}, WithSortedHelp()), "alpha", "zeta")
+}
+
+func TestParseAttributesOrder(t *testing.T) {
+	attribs := &arg{}
+	if err := parseAttribute("name", "order=-3", attribs); err != nil {
+		t.Fatal(err)
+	}
+	if !attribs.hasOrder || attribs.order != -3 {
+		t.Fatalf("expected order '-3', got '%d'", attribs.order)
+	}
+
+	attribs = &arg{}
+	if err := parseAttribute("name", "order=first", attribs); err == nil {
+		t.Fatal("expected error")
+	}
+
+	attribs = &arg{}
+	if err := parseAttribute("name", "group=Network", attribs); err != nil {
+		t.Fatal(err)
+	}
+	if attribs.group != "Network" {
+		t.Fatalf("expected 'Network', got '%s'", attribs.group)
+	}
+}
@@ -0,0 +1,559 @@
+package argo
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type Shell string
+
+const (
+	ShellBash Shell = "bash"
+	ShellZsh  Shell = "zsh"
+	ShellFish Shell = "fish"
+)
+
+var completionIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type CompletionFunc func(prefix string) []string
+
+type completionCommand struct {
+	path       string
+	flags      []*arg
+	positional []*arg
+	commands   []*command
+}
+
+func GenerateCompletion(input interface{}, shell Shell) (string, error) {
+	return defaultParser.GenerateCompletion(input, shell)
+}
+
+func (p *Parser) GenerateCompletion(input interface{}, shell Shell) (string, error) {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return "", err
+	}
+
+	program := filepath.Base(p.getProgramName())
+	commands := argumentsRegistry.completionCommands()
+	switch shell {
+	case ShellBash:
+		return bashCompletion(program, commands), nil
+	case ShellZsh:
+		return zshCompletion(program, commands), nil
+	case ShellFish:
+		return fishCompletion(program, commands), nil
+	default:
+		return "", ErrUnsupportedShell
+	}
+}
+
+func (r *argsRegistry) completionCommands() []completionCommand {
+	cmd := completionCommand{
+		path:       r.path(),
+		positional: r.positional,
+		commands:   r.helpCommands(),
+	}
+	for registry := r; registry != nil; registry = registry.parent {
+		for _, argument := range registry.helpArgs() {
+			if argument.short != "" || argument.long != "" {
+				cmd.flags = append(cmd.flags, argument)
+			}
+		}
+	}
+	cmd.flags = append(cmd.flags, r.parser.builtinArgs()...)
+
+	commands := []completionCommand{cmd}
+	for _, child := range cmd.commands {
+		commands = append(commands, child.registry.completionCommands()...)
+	}
+	return commands
+}
+
+func (r *argsRegistry) complete(args []string) error {
+	current := ""
+	if len(args) > 0 {
+		current = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	registry := r
+	var pending *arg
+	positionalIndex := 0
+	explicitPositional := false
+	for _, argText := range args {
+		if pending != nil {
+			if argText != longValueSeparator {
+				pending = nil
+			}
+			continue
+		}
+
+		switch {
+		case explicitPositional:
+			positionalIndex++
+		case argText == "--":
+			explicitPositional = true
+		case strings.HasPrefix(argText, "--"):
+			if _, _, hasValue := strings.Cut(argText, longValueSeparator); hasValue {
+				continue
+			}
+			if argument := registry.lookupCompletionLong(argText[2:]); argument != nil && !argument.isFlag {
+				pending = argument
+			}
+		case strings.HasPrefix(argText, "-"):
+			for j := 1; j < len(argText); j++ {
+				if argument := registry.lookupShort(argText[j : j+1]); argument != nil && !argument.isFlag {
+					if j == len(argText)-1 {
+						pending = argument
+					}
+					break
+				}
+			}
+		default:
+			if cmd, ok := registry.commands[argText]; ok {
+				registry = cmd.registry
+				continue
+			}
+			positionalIndex++
+		}
+	}
+
+	prefix := ""
+	if pending == nil && !explicitPositional && strings.HasPrefix(current, "--") {
+		if name, value, hasValue := strings.Cut(current[2:], longValueSeparator); hasValue {
+			if argument := registry.lookupCompletionLong(name); argument != nil && !argument.isFlag {
+				pending = argument
+				prefix = current[:len(current)-len(value)]
+				current = value
+			}
+		}
+	}
+
+	if pending != nil && current == longValueSeparator {
+		current = ""
+	}
+
+	argument := pending
+	if argument == nil && (explicitPositional || !strings.HasPrefix(current, "-")) && len(registry.positional) > 0 {
+		argument = registry.positional[min(positionalIndex, len(registry.positional)-1)]
+		if positionalIndex >= len(registry.positional) && !argument.isRepeated {
+			argument = nil
+		}
+	}
+
+	if argument != nil && argument.completer != nil {
+		for _, candidate := range argument.completer(current) {
+			if !strings.HasPrefix(candidate, current) {
+				continue
+			}
+			if _, err := io.WriteString(r.parser.stdout, prefix+candidate+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return ErrCompletion
+}
+
+func (r *argsRegistry) lookupCompletionLong(name string) *arg {
+	if argument := r.lookupLong(name); argument != nil {
+		return argument
+	}
+	return r.parser.lookupBuiltin(name)
+}
+
+func (c completionCommand) isDynamic() bool {
+	for _, argument := range c.flags {
+		if argument.completer != nil {
+			return true
+		}
+	}
+	for _, argument := range c.positional {
+		if argument.completer != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isDynamic(commands []completionCommand) bool {
+	for _, cmd := range commands {
+		if cmd.isDynamic() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *arg) flagNames() []string {
+	names := make([]string, 0, 3)
+	if a.short != "" {
+		names = append(names, "-"+a.short)
+	}
+	if a.long != "" {
+		names = append(names, "--"+a.long)
+	}
+	return names
+}
+
+func (a *arg) completesFiles() bool {
+	return a.kind == reflect.String && !a.isMap
+}
+
+func completionIdentifier(parts ...string) string {
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	return completionIdentifierRegex.ReplaceAllString(strings.Join(names, "_"), "_")
+}
+
+func completionCommandNames(commands []*command) []string {
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+func bashCompletion(program string, commands []completionCommand) string {
+	function := "_" + completionIdentifier(program) + "_completion"
+	dynamic := "_" + completionIdentifier(program) + "_dynamic"
+
+	output := fmt.Sprintf("# bash completion for %s\n\n", program)
+	if isDynamic(commands) {
+		output += fmt.Sprintf("%s() {\n", dynamic)
+		output += "    local IFS=$'\\n'\n"
+		output += fmt.Sprintf("    COMPREPLY=($(compgen -W \"$(\"${COMP_WORDS[0]}\" %s \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null)\" -- \"$cur\"))\n", completeCommand)
+		output += "}\n\n"
+	}
+	output += fmt.Sprintf("%s() {\n", function)
+	output += "    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n"
+	output += "    local path=\"\" skip=0 positional=0 dashdash=0 word i\n"
+	output += "    for ((i = 1; i < COMP_CWORD; i++)); do\n"
+	output += "        word=\"${COMP_WORDS[i]}\"\n"
+	output += "        if ((skip)); then\n            [[ \"$word\" == \"=\" ]] || skip=0\n            continue\n        fi\n"
+	output += "        if ((dashdash)); then\n            positional=$((positional + 1))\n            continue\n        fi\n"
+	output += "        case \"$path\" in\n"
+	for _, cmd := range commands {
+		output += fmt.Sprintf("        %q)\n", cmd.path)
+		output += "            case \"$word\" in\n"
+		output += "            --) dashdash=1 ;;\n"
+		if values := valueFlagNames(cmd.flags); len(values) > 0 {
+			output += fmt.Sprintf("            %s) skip=1 ;;\n", strings.Join(values, "|"))
+		}
+		output += "            -*) ;;\n"
+		for _, child := range cmd.commands {
+			output += fmt.Sprintf("            %s) path=%q ;;\n", child.name, strings.TrimSpace(cmd.path+" "+child.name))
+		}
+		output += "            *) positional=$((positional + 1)) ;;\n"
+		output += "            esac\n"
+		output += "            ;;\n"
+	}
+	output += "        esac\n"
+	output += "    done\n\n"
+
+	if isDynamic(commands) {
+		output += fmt.Sprintf("    if ((skip)); then\n        [[ \"$cur\" == \"=\" ]] && cur=\"\"\n        %s\n        return\n    fi\n\n", dynamic)
+	} else {
+		output += "    if ((skip)); then\n        return\n    fi\n\n"
+	}
+
+	output += "    if ((!dashdash)) && [[ \"$cur\" == -* ]]; then\n"
+	output += "        case \"$path\" in\n"
+	for _, cmd := range commands {
+		words := make([]string, 0, len(cmd.flags))
+		for _, flag := range cmd.flags {
+			words = append(words, flag.flagNames()...)
+			if flag.isNegatable {
+				words = append(words, "--"+negationPrefix+flag.long)
+			}
+		}
+		output += fmt.Sprintf("        %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", cmd.path, strings.Join(words, " "))
+	}
+	output += "        esac\n"
+	output += "        return\n"
+	output += "    fi\n\n"
+
+	output += "    case \"$path\" in\n"
+	for _, cmd := range commands {
+		if len(cmd.commands) > 0 {
+			output += fmt.Sprintf("    %q)\n", cmd.path)
+			output += "        if ((!dashdash && positional == 0)); then\n"
+			output += fmt.Sprintf("            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(completionCommandNames(cmd.commands), " "))
+			output += "        fi\n"
+			output += "        ;;\n"
+			continue
+		}
+
+		positionals := ""
+		hasCompletion := false
+		for i, positional := range cmd.positional {
+			pattern := fmt.Sprint(i)
+			if positional.isRepeated {
+				pattern = "*"
+			}
+			switch {
+			case positional.completer != nil:
+				positionals += fmt.Sprintf("        %s) %s ;;\n", pattern, dynamic)
+				hasCompletion = true
+			case positional.completesFiles():
+				positionals += fmt.Sprintf("        %s)\n            compopt -o filenames 2>/dev/null\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n            ;;\n", pattern)
+				hasCompletion = true
+			default:
+				positionals += fmt.Sprintf("        %s) ;;\n", pattern)
+			}
+		}
+		if hasCompletion {
+			output += fmt.Sprintf("    %q)\n", cmd.path)
+			output += "        case \"$positional\" in\n" + positionals + "        esac\n"
+			output += "        ;;\n"
+		}
+	}
+	output += "    esac\n"
+	output += "}\n\n"
+
+	output += fmt.Sprintf("complete -F %s %s\n", function, program)
+	return output
+}
+
+func valueFlagNames(flags []*arg) []string {
+	values := make([]string, 0)
+	for _, flag := range flags {
+		if !flag.isFlag {
+			values = append(values, flag.flagNames()...)
+		}
+	}
+	return values
+}
+
+func zshCompletion(program string, commands []completionCommand) string {
+	root := "_" + completionIdentifier(program)
+	dynamic := root + "_dynamic"
+
+	output := fmt.Sprintf("#compdef %s\n", program)
+	if isDynamic(commands) {
+		output += fmt.Sprintf("\n%s() {\n", dynamic)
+		output += "    local -a candidates\n"
+		output += fmt.Sprintf("    candidates=(${(f)\"$(\"${%s_words[1]}\" %s \"${(@)%s_words[2,%s_current]}\" 2>/dev/null)\"})\n", root, completeCommand, root, root)
+		output += "    compadd -a candidates\n"
+		output += "}\n"
+	}
+
+	for _, cmd := range commands {
+		function := "_" + completionIdentifier(program, cmd.path)
+
+		specs := make([]string, 0, len(cmd.flags)+len(cmd.positional)+2)
+		for _, flag := range cmd.flags {
+			specs = append(specs, zshFlagSpecs(flag, dynamic)...)
+		}
+		for i, positional := range cmd.positional {
+			action := " "
+			if positional.completer != nil {
+				action = dynamic
+			} else if positional.completesFiles() {
+				action = "_files"
+			}
+			position := fmt.Sprint(i + 1)
+			if positional.isRepeated {
+				position = "*"
+			}
+			specs = append(specs, fmt.Sprintf("'%s:%s:%s'", position, zshEscape(positional.name), action))
+		}
+
+		output += fmt.Sprintf("\n%s() {\n", function)
+		if function == root && isDynamic(commands) {
+			output += fmt.Sprintf("    local -a %s_words\n", root)
+			output += fmt.Sprintf("    %s_words=(\"${words[@]}\")\n", root)
+			output += fmt.Sprintf("    local %s_current=$CURRENT\n", root)
+		}
+		if len(cmd.commands) == 0 {
+			output += "    _arguments -s \\\n        " + strings.Join(specs, " \\\n        ") + "\n"
+			output += "}\n"
+			continue
+		}
+
+		specs = append(specs, "'1: :->command'", "'*:: :->args'")
+		output += "    local curcontext=\"$curcontext\" state line\n"
+		output += "    _arguments -C -s \\\n        " + strings.Join(specs, " \\\n        ") + "\n\n"
+		output += "    case $state in\n"
+		output += "    command)\n"
+		output += "        local -a commands\n"
+		output += "        commands=(\n"
+		for _, child := range cmd.commands {
+			description := strings.ReplaceAll(zshEscape(child.name), ":", `\:`)
+			if child.help != "" {
+				description += ":" + zshEscape(child.help)
+			}
+			output += fmt.Sprintf("            '%s'\n", description)
+		}
+		output += "        )\n"
+		output += "        _describe -t commands command commands\n"
+		output += "        ;;\n"
+		output += "    args)\n"
+		output += "        case $line[1] in\n"
+		for _, child := range cmd.commands {
+			output += fmt.Sprintf("        %s) _%s ;;\n", child.name, completionIdentifier(program, cmd.path, child.name))
+		}
+		output += "        esac\n"
+		output += "        ;;\n"
+		output += "    esac\n"
+		output += "}\n"
+	}
+
+	output += fmt.Sprintf("\nif [ \"$funcstack[1]\" = %q ]; then\n", root)
+	output += fmt.Sprintf("    %s \"$@\"\n", root)
+	output += "else\n"
+	output += fmt.Sprintf("    compdef %s %s\n", root, program)
+	output += "fi\n"
+	return output
+}
+
+func zshFlagSpecs(flag *arg, dynamic string) []string {
+	names := flag.flagNames()
+	value := ""
+	if !flag.isFlag {
+		action := " "
+		if flag.completer != nil {
+			action = dynamic
+		}
+		value = fmt.Sprintf(":%s:%s", strings.Trim(flag.metavar, "<>"), action)
+	}
+	description := fmt.Sprintf("[%s]", zshEscape(flag.help))
+
+	if flag.isNegatable {
+		negation := "--" + negationPrefix + flag.long
+		exclusion := fmt.Sprintf("'(%s %s)", strings.Join(names, " "), negation)
+		if len(names) > 1 {
+			return []string{exclusion + "'{" + strings.Join(names, ",") + "}'" + description + "'", exclusion + negation + description + "'"}
+		}
+		return []string{exclusion + names[0] + description + "'", exclusion + negation + description + "'"}
+	}
+
+	prefix := fmt.Sprintf("'(%s)", strings.Join(names, " "))
+	if flag.isRepeated || flag.isCounter {
+		prefix = "'*"
+	}
+	if len(names) > 1 {
+		return []string{prefix + "'{" + strings.Join(names, ",") + "}'" + description + value + "'"}
+	}
+	return []string{prefix + names[0] + description + value + "'"}
+}
+
+func zshEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "[", `\[`)
+	text = strings.ReplaceAll(text, "]", `\]`)
+	return strings.ReplaceAll(text, "'", `'\''`)
+}
+
+func fishCompletion(program string, commands []completionCommand) string {
+	function := "__" + completionIdentifier(program) + "_command"
+
+	output := fmt.Sprintf("# fish completion for %s\n\n", program)
+	output += fmt.Sprintf("function %s\n", function)
+	output += "    set -l path ''\n"
+	output += "    set -l skip 0\n"
+	output += "    for word in (commandline -opc)[2..-1]\n"
+	output += "        if test $skip -eq 1\n            set skip 0\n            continue\n        end\n"
+	output += "        if test \"$word\" = --\n            break\n        end\n"
+	output += "        switch $path\n"
+	for _, cmd := range commands {
+		values := valueFlagNames(cmd.flags)
+		if len(values) == 0 && len(cmd.commands) == 0 {
+			continue
+		}
+		output += fmt.Sprintf("            case %s\n", fishQuote(cmd.path))
+		output += "                switch $word\n"
+		if len(values) > 0 {
+			output += fmt.Sprintf("                    case %s\n                        set skip 1\n", strings.Join(values, " "))
+		}
+		if len(cmd.commands) > 0 {
+			output += fmt.Sprintf("                    case %s\n                        set path (string trim -- \"$path $word\")\n", strings.Join(completionCommandNames(cmd.commands), " "))
+		}
+		output += "                end\n"
+	}
+	output += "        end\n"
+	output += "    end\n"
+	output += "    echo $path\n"
+	output += "end\n\n"
+
+	dynamic := "__" + completionIdentifier(program) + "_dynamic"
+	if isDynamic(commands) {
+		output += fmt.Sprintf("function %s\n", dynamic)
+		output += "    set -l words (commandline -opc)\n"
+		output += fmt.Sprintf("    $words[1] %s $words[2..-1] (commandline -ct) 2>/dev/null\n", completeCommand)
+		output += "end\n\n"
+	}
+
+	output += fmt.Sprintf("function %s_is\n", function)
+	output += fmt.Sprintf("    set -l path (%s)\n", function)
+	output += "    test \"$path\" = \"$argv[1]\"\n"
+	output += "end\n\n"
+
+	output += fmt.Sprintf("complete -c %s -f\n", program)
+	for _, cmd := range commands {
+		condition := fmt.Sprintf("-n %s", fishQuote(fmt.Sprintf("%s_is %s", function, fishQuote(cmd.path))))
+		for _, flag := range cmd.flags {
+			arguments := ""
+			if flag.completer != nil {
+				arguments = fmt.Sprintf("(%s)", dynamic)
+			}
+			output += fmt.Sprintf("complete -c %s %s%s\n", program, condition, fishFlag(flag.short, flag.long, flag.isFlag, arguments, flag.help))
+			if flag.isNegatable {
+				output += fmt.Sprintf("complete -c %s %s%s\n", program, condition, fishFlag("", negationPrefix+flag.long, true, "", flag.help))
+			}
+		}
+		for _, child := range cmd.commands {
+			output += fmt.Sprintf("complete -c %s %s -a %s", program, condition, fishQuote(child.name))
+			if child.help != "" {
+				output += " -d " + fishQuote(child.help)
+			}
+			output += "\n"
+		}
+		for _, positional := range cmd.positional {
+			if positional.completer != nil {
+				output += fmt.Sprintf("complete -c %s %s -a %s\n", program, condition, fishQuote(fmt.Sprintf("(%s)", dynamic)))
+				break
+			}
+		}
+		for _, positional := range cmd.positional {
+			if positional.completesFiles() && positional.completer == nil {
+				output += fmt.Sprintf("complete -c %s %s -F\n", program, condition)
+				break
+			}
+		}
+	}
+	return output
+}
+
+func fishFlag(short, long string, isFlag bool, arguments string, help string) string {
+	output := ""
+	if short != "" {
+		output += " -s " + short
+	}
+	if long != "" {
+		output += " -l " + long
+	}
+	if !isFlag {
+		output += " -x"
+	}
+	if arguments != "" {
+		output += " -a " + fishQuote(arguments)
+	}
+	if help != "" {
+		output += " -d " + fishQuote(help)
+	}
+	return output
+}
+
+func fishQuote(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	return "'" + strings.ReplaceAll(text, "'", `\'`) + "'"
+}
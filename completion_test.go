@@ -0,0 +1,212 @@
+package argo
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+type argsCompletion struct {
+	Verbose  int                 `argo:"short,long,count,help=More output"`
+	Color    bool                `argo:"long,negatable,help=Use [colors]"`
+	Deploy   *argsCompletionFile `argo:"cmd,help=Deploy the service"`
+	Rollback *struct {
+		ID   int    `argo:"positional"`
+		Path string `argo:"positional"`
+	} `argo:"cmd"`
+}
+
+type argsCompletionFile struct {
+	Env   string   `argo:"short,long,required"`
+	Files []string `argo:"positional"`
+}
+
+func generateCompletion(t *testing.T, shell Shell) string {
+	script, err := New(WithProgramName("./my-tool")).GenerateCompletion(&argsCompletion{}, shell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func assertContains(t *testing.T, text string, expected ...string) {
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Fatalf("expected '%s' in:\n%s", e, text)
+		}
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	script := generateCompletion(t, ShellBash)
+	assertContains(t, script,
+		"_my_tool_completion() {\n",
+		"            deploy) path=\"deploy\" ;;\n",
+		"            -e|--env) skip=1 ;;\n",
+		"        \"\") COMPREPLY=($(compgen -W \"-v --verbose --color --no-color -h --help\" -- \"$cur\")) ;;\n",
+		"            COMPREPLY=($(compgen -W \"deploy rollback\" -- \"$cur\"))\n",
+		"        0) ;;\n        1)\n            compopt -o filenames 2>/dev/null\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n",
+		"complete -F _my_tool_completion my-tool\n",
+	)
+}
+
+func TestZshCompletion(t *testing.T) {
+	script := generateCompletion(t, ShellZsh)
+	assertContains(t, script,
+		"#compdef my-tool\n",
+		"        '*'{-v,--verbose}'[More output]' \\\n",
+		"        '(--color --no-color)--no-color[Use \\[colors\\]]' \\\n",
+		"        '(-e --env)'{-e,--env}'[]:string: ' \\\n",
+		"            'deploy:Deploy the service'\n            'rollback'\n",
+		"        deploy) _my_tool_deploy ;;\n",
+		"        '*:Files:_files'\n",
+		"        '1:ID: ' \\\n        '2:Path:_files'\n",
+		"    compdef _my_tool my-tool\n",
+	)
+}
+
+func TestFishCompletion(t *testing.T) {
+	script := generateCompletion(t, ShellFish)
+	assertContains(t, script,
+		"function __my_tool_command\n",
+		"                    case -e --env\n                        set skip 1\n",
+		"complete -c my-tool -f\n",
+		"complete -c my-tool -n '__my_tool_command_is \\'\\'' -s v -l verbose -d 'More output'\n",
+		"complete -c my-tool -n '__my_tool_command_is \\'\\'' -l no-color -d 'Use [colors]'\n",
+		"complete -c my-tool -n '__my_tool_command_is \\'\\'' -a 'deploy' -d 'Deploy the service'\n",
+		"complete -c my-tool -n '__my_tool_command_is \\'deploy\\'' -s e -l env -x\n",
+		"complete -c my-tool -n '__my_tool_command_is \\'rollback\\'' -F\n",
+	)
+}
+
+func TestCompletionUnsupportedShell(t *testing.T) {
+	if _, err := GenerateCompletion(&argsCompletion{}, Shell("powershell")); !errors.Is(err, ErrUnsupportedShell) {
+		t.Fatalf("expected ErrUnsupportedShell, got %v", err)
+	}
+}
+
+type argsDynamicCompletion struct {
+	Cluster string `argo:"short,long,complete=clusters"`
+	Verbose bool   `argo:"short,long"`
+	Deploy  *struct {
+		Target string   `argo:"positional,complete=clusters"`
+		Files  []string `argo:"positional"`
+	} `argo:"cmd"`
+}
+
+func TestCompleteCallback(t *testing.T) {
+	prefixes := make([]string, 0)
+	clusters := func(prefix string) []string {
+		prefixes = append(prefixes, prefix)
+		return []string{"prod-eu", "prod-us", "staging"}
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"__complete", "--cluster", "prod"}, "prod-eu\nprod-us\n"},
+		{[]string{"__complete", "-vc", "s"}, "staging\n"},
+		{[]string{"__complete", "--cluster=prod"}, "--cluster=prod-eu\n--cluster=prod-us\n"},
+		{[]string{"__complete", "--cluster", "=", "st"}, "staging\n"},
+		{[]string{"__complete", "--cluster", "="}, "prod-eu\nprod-us\nstaging\n"},
+		{[]string{"__complete", "--cluster=prod", "deploy", ""}, "prod-eu\nprod-us\nstaging\n"},
+		{[]string{"__complete", "deploy", "-v", ""}, "prod-eu\nprod-us\nstaging\n"},
+		{[]string{"__complete", "deploy", "staging", ""}, ""},
+		{[]string{"__complete", "--verbose", ""}, ""},
+		{[]string{"__complete"}, ""},
+	}
+	for _, test := range tests {
+		output := &strings.Builder{}
+		parser := New(WithCompletion("clusters", clusters), WithStdout(output))
+		if err := parser.ParseArgs(&argsDynamicCompletion{}, test.args); !errors.Is(err, ErrCompletion) {
+			t.Fatalf("%v: expected ErrCompletion, got %v", test.args, err)
+		}
+		if output.String() != test.expected {
+			t.Fatalf("%v: expected '%s', got '%s'", test.args, test.expected, output.String())
+		}
+	}
+	if prefixes[0] != "prod" {
+		t.Fatalf("expected prefix 'prod', got '%s'", prefixes[0])
+	}
+}
+
+func TestCompleteUnknownFunction(t *testing.T) {
+	err := ParseArgs(&argsDynamicCompletion{}, []string{})
+	var parseErr *ParseError
+	if !errors.Is(err, ErrUnknownCompletion) || !errors.As(err, &parseErr) || parseErr.Name != "clusters" {
+		t.Fatalf("expected ErrUnknownCompletion for clusters, got %v", err)
+	}
+}
+
+func TestDynamicCompletionScripts(t *testing.T) {
+	parser := New(WithProgramName("tool"), WithCompletion("clusters", func(string) []string { return nil }))
+
+	bash, err := parser.GenerateCompletion(&argsDynamicCompletion{}, ShellBash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, bash,
+		"    COMPREPLY=($(compgen -W \"$(\"${COMP_WORDS[0]}\" __complete \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null)\" -- \"$cur\"))\n",
+		"    if ((skip)); then\n        [[ \"$cur\" == \"=\" ]] && cur=\"\"\n        _tool_dynamic\n        return\n    fi\n",
+		"        0) _tool_dynamic ;;\n",
+	)
+
+	zsh, err := parser.GenerateCompletion(&argsDynamicCompletion{}, ShellZsh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, zsh,
+		"    _tool_words=(\"${words[@]}\")\n",
+		"'(-c --cluster)'{-c,--cluster}'[]:string:_tool_dynamic'",
+		"'1:Target:_tool_dynamic'",
+	)
+
+	fish, err := parser.GenerateCompletion(&argsDynamicCompletion{}, ShellFish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, fish,
+		"    $words[1] __complete $words[2..-1] (commandline -ct) 2>/dev/null\n",
+		"-s c -l cluster -x -a '(__tool_dynamic)'\n",
+		"complete -c tool -n '__tool_command_is \\'deploy\\'' -a '(__tool_dynamic)'\n",
+	)
+}
+
+func TestBashCompletionValueSeparator(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	parser := New(WithProgramName("tool"), WithCompletion("clusters", func(string) []string { return nil }))
+	script, err := parser.GenerateCompletion(&argsDynamicCompletion{}, ShellBash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		words    string
+		expected string
+	}{
+		{`(tool --cluster = "")`, "prod-eu prod-us"},
+		{`(tool --cluster =)`, "prod-eu prod-us"},
+		{`(tool --cluster = prod "")`, "deploy"},
+		{`(tool --cluster "")`, "prod-eu prod-us"},
+	}
+	for _, test := range tests {
+		command := script + `
+tool() { printf 'prod-eu\nprod-us\n'; }
+COMP_WORDS=` + test.words + `
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+_tool_completion
+printf '%s' "${COMPREPLY[*]}"
+`
+		output, err := exec.Command("bash", "-c", command).Output()
+		if err != nil {
+			t.Fatalf("%s: %v", test.words, err)
+		}
+		if string(output) != test.expected {
+			t.Fatalf("%s: expected '%s', got '%s'", test.words, test.expected, output)
+		}
+	}
+}
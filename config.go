@@ -0,0 +1,260 @@
+package argo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const configKeySeparator string = "."
+
+type ConfigSource interface {
+	Load() (map[string]interface{}, error)
+}
+
+type ConfigDecoder func(data []byte) (map[string]interface{}, error)
+
+type ConfigMap map[string]interface{}
+
+func (m ConfigMap) Load() (map[string]interface{}, error) {
+	return m, nil
+}
+
+var configDecoders = map[string]ConfigDecoder{
+	".json": DecodeJSON,
+	".yaml": DecodeYAML,
+	".yml":  DecodeYAML,
+	".toml": DecodeTOML,
+}
+
+type configFile struct {
+	path    string
+	decoder ConfigDecoder
+}
+
+func ConfigFile(path string) ConfigSource {
+	return &configFile{path: path}
+}
+
+func ConfigFileWithDecoder(path string, decoder ConfigDecoder) ConfigSource {
+	return &configFile{path: path, decoder: decoder}
+}
+
+func (f *configFile) Load() (map[string]interface{}, error) {
+	decoder := f.decoder
+	if decoder == nil {
+		decoder = configDecoders[strings.ToLower(filepath.Ext(f.path))]
+	}
+	if decoder == nil {
+		decoder = DecodeJSON
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return decoder(data)
+}
+
+func (f *configFile) String() string {
+	return f.path
+}
+
+func DecodeJSON(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	config := make(map[string]interface{})
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("json: unexpected data after top-level value")
+	}
+	return config, nil
+}
+
+func (r *argsRegistry) loadConfig() error {
+	sources := r.parser.configSources
+	switch {
+	case r.configPath != "":
+		sources = append(sources[:len(sources):len(sources)], ConfigFile(r.configPath))
+	case r.parser.configFile != "":
+		sources = append(sources[:len(sources):len(sources)], ConfigFile(r.parser.configFile))
+	}
+
+	for _, source := range sources {
+		config, err := source.Load()
+		if err != nil {
+			parseErr := &ParseError{Err: ErrInvalidConfig, Cause: err}
+			if name, ok := source.(fmt.Stringer); ok {
+				parseErr.Name = name.String()
+			}
+			if err := r.fail(parseErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.applyConfig(config, "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *argsRegistry) applyConfig(config map[string]interface{}, prefix string, group string) error {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := config[key]
+		name := prefix + key
+
+		if argument := r.lookupConfig(key, group); argument != nil {
+			if value != nil {
+				argument.configName = name
+				argument.configValue = value
+			}
+			continue
+		}
+
+		nested, isNested := value.(map[string]interface{})
+		if cmd, ok := r.commands[key]; ok && group == "" {
+			if !isNested {
+				if err := r.fail(&ParseError{Err: ErrInvalidConfigValue, Name: name, Source: SourceConfig}); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := cmd.registry.applyConfig(nested, name+configKeySeparator, ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if nestedGroup, ok := r.configGroups[key]; ok && isNested {
+			if err := r.applyConfig(nested, name+configKeySeparator, nestedGroup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := &ParseError{Err: ErrUnknownConfigKey, Name: name, Suggestions: suggest(key, r.configKeys())}
+		if err := r.fail(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *argsRegistry) lookupConfig(key string, group string) *arg {
+	for _, argument := range r.args {
+		if argument.getConfigKey() == key && (group == "" || argument.group == group) {
+			return argument
+		}
+	}
+	return nil
+}
+
+func (r *argsRegistry) configKeys() []string {
+	keys := make([]string, 0, len(r.args)+len(r.commandList))
+	for _, argument := range r.args {
+		keys = append(keys, argument.getConfigKey())
+	}
+	for _, cmd := range r.commandList {
+		keys = append(keys, cmd.name)
+	}
+	for key := range r.configGroups {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (a *arg) getConfigKey() string {
+	switch {
+	case a.configKey != "":
+		return a.configKey
+	case a.long != "":
+		return a.long
+	default:
+		return strings.ToLower(a.name)
+	}
+}
+
+func (a *arg) setConfig() error {
+	switch value := a.configValue.(type) {
+	case []interface{}:
+		if !a.isRepeated || a.isMap {
+			return &ParseError{Err: ErrCouldNotSet, Field: a.name, Name: a.configName, Source: SourceConfig, Cause: ErrInvalidConfigValue}
+		}
+		for _, elem := range value {
+			if err := a.setConfigValue(elem, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if !a.isMap {
+			return &ParseError{Err: ErrCouldNotSet, Field: a.name, Name: a.configName, Source: SourceConfig, Cause: ErrInvalidConfigValue}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := a.setConfigValue(value[key], key+mapEntrySeparator); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		text, err := configString(value)
+		if err != nil {
+			return newSetError(a, a.configName, "", SourceConfig, err)
+		}
+		if err := a.setJoined(text); err != nil {
+			return newSetError(a, a.configName, text, SourceConfig, err)
+		}
+		return nil
+	}
+}
+
+func (a *arg) setConfigValue(value interface{}, prefix string) error {
+	text, err := configString(value)
+	if err != nil {
+		return newSetError(a, a.configName, "", SourceConfig, err)
+	}
+	if err := a.setter(prefix + text); err != nil {
+		return newSetError(a, a.configName, prefix+text, SourceConfig, err)
+	}
+	return nil
+}
+
+func configString(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case json.Number:
+		return value.String(), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	default:
+		return "", ErrInvalidConfigValue
+	}
+}
@@ -0,0 +1,256 @@
+package argo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type argsConfig struct {
+	Address string            `argo:"short=a,long=addr,default=localhost"`
+	Port    int               `argo:"short,long,env=PORT,default=80"`
+	Verbose int               `argo:"short,long,count"`
+	Color   bool              `argo:"long,negatable,default=true"`
+	Tags    []string          `argo:"long=tag"`
+	Labels  map[string]string `argo:"long=label"`
+	Token   string            `argo:"env=TOKEN,config=api_token,required"`
+	Deploy  *argsDeploy       `argo:"cmd"`
+}
+
+func writeConfig(t *testing.T, content string) string {
+	return writeConfigFile(t, "config.json", content)
+}
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func TestConfigFile(t *testing.T) {
+	path := writeConfig(t, `{
+		"addr": "example.com",
+		"port": 8080,
+		"verbose": 2,
+		"color": false,
+		"tag": ["a", "b"],
+		"label": {"tier": "web", "team": "infra"},
+		"api_token": "secret",
+		"deploy": {"env": "prod", "force": true}
+	}`)
+
+	args := argsConfig{}
+	if err := ParseArgs(&args, []string{"deploy"}, WithConfigFile(path), WithEnvLookup(noEnv)); err != nil {
+		t.Fatal(err)
+	}
+	if args.Address != "example.com" || args.Port != 8080 || args.Verbose != 2 || args.Color || args.Token != "secret" {
+		t.Fatalf("unexpected values: %+v", args)
+	}
+	if len(args.Tags) != 2 || args.Tags[0] != "a" || args.Tags[1] != "b" {
+		t.Fatalf("unexpected tags: %v", args.Tags)
+	}
+	if len(args.Labels) != 2 || args.Labels["team"] != "infra" || args.Labels["tier"] != "web" {
+		t.Fatalf("unexpected labels: %v", args.Labels)
+	}
+	if args.Deploy == nil || args.Deploy.Env != "prod" || !args.Deploy.Force {
+		t.Fatalf("unexpected deploy: %+v", args.Deploy)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	path := writeConfig(t, `{"addr": "config", "port": 8080, "api_token": "config"}`)
+	env := func(key string) (string, bool) {
+		if key == "PORT" {
+			return "9090", true
+		}
+		return "", false
+	}
+
+	args := argsConfig{}
+	if err := ParseArgs(&args, []string{"--addr", "flag"}, WithConfigFile(path), WithEnvLookup(env)); err != nil {
+		t.Fatal(err)
+	}
+	if args.Address != "flag" || args.Port != 9090 || args.Token != "config" || !args.Color {
+		t.Fatalf("unexpected values: %+v", args)
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	path := writeConfig(t, `{"api_token": "secret"}`)
+	opts := []Option{WithConfigFlag("config"), WithConfigFile("missing.json"), WithEnvLookup(noEnv)}
+
+	for _, input := range [][]string{{"--config", path}, {"--config=" + path}} {
+		args := argsConfig{}
+		if err := ParseArgs(&args, input, opts...); err != nil {
+			t.Fatal(err)
+		}
+		if args.Token != "secret" {
+			t.Fatalf("expected 'secret', got '%s'", args.Token)
+		}
+	}
+
+	if err := ParseArgs(&argsConfig{}, []string{}, opts...); !errors.Is(err, ErrInvalidConfig) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrInvalidConfig, got '%v'", err)
+	}
+	if err := ParseArgs(&argsConfig{}, []string{"--config"}, opts...); !errors.Is(err, ErrMissingValue) {
+		t.Fatalf("expected ErrMissingValue, got '%v'", err)
+	}
+	if usage := Usage(&argsConfig{}, opts...); !strings.Contains(usage, "      --config <file>") {
+		t.Fatalf("expected config flag in help, got:\n%s", usage)
+	}
+}
+
+func TestConfigUnknownKey(t *testing.T) {
+	path := writeConfig(t, `{"prot": 1, "api_token": "secret", "deploy": {"envv": "prod"}}`)
+
+	err := ParseArgs(&argsConfig{}, []string{}, WithConfigFile(path), WithEnvLookup(noEnv), WithAllErrors())
+	var parseErrors *ParseErrors
+	if !errors.As(err, &parseErrors) || len(parseErrors.Errors) != 2 {
+		t.Fatalf("expected two errors, got '%v'", err)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(parseErrors.Errors[0], &parseErr) || !errors.Is(parseErr, ErrUnknownConfigKey) || parseErr.Name != "deploy.envv" {
+		t.Fatalf("unexpected error '%v'", parseErrors.Errors[0])
+	}
+	if !errors.As(parseErrors.Errors[1], &parseErr) || parseErr.Name != "prot" || len(parseErr.Suggestions) == 0 || parseErr.Suggestions[0] != "port" {
+		t.Fatalf("unexpected error '%v'", parseErrors.Errors[1])
+	}
+}
+
+func TestConfigInvalid(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected error
+	}{
+		{`{"port": `, ErrInvalidConfig},
+		{`["port"]`, ErrInvalidConfig},
+		{`{"port": 1} {}`, ErrInvalidConfig},
+		{`{"port": "abc", "api_token": "x"}`, ErrCouldNotSet},
+		{`{"port": [1, 2], "api_token": "x"}`, ErrInvalidConfigValue},
+		{`{"tag": [{"a": 1}], "api_token": "x"}`, ErrInvalidConfigValue},
+		{`{"deploy": "prod", "api_token": "x"}`, ErrInvalidConfigValue},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, test.content)
+		if err := ParseArgs(&argsConfig{}, []string{}, WithConfigFile(path), WithEnvLookup(noEnv)); !errors.Is(err, test.expected) {
+			t.Fatalf("%s: expected '%v', got '%v'", test.content, test.expected, err)
+		}
+	}
+
+	if _, err := DecodeJSON([]byte(`{"port": 1} {}`)); err == nil || errors.Is(err, ErrInvalidConfigValue) {
+		t.Fatalf("expected a syntax error, got '%v'", err)
+	}
+}
+
+func TestConfigPositional(t *testing.T) {
+	path := writeConfig(t, `{"id": 42}`)
+	args := argsRollback{}
+	if err := ParseArgs(&args, []string{}, WithConfigFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	if args.ID != 42 {
+		t.Fatalf("expected 42, got %d", args.ID)
+	}
+}
+
+type argsConfigGroups struct {
+	Verbose bool              `argo:"short,long"`
+	Network argsNetwork       `argo:"group,help=Settings of the network layer"`
+	Storage argsStorage       `argo:"group=Storage,config=store"`
+	Tags    []string          `argo:"long=tag"`
+	Labels  map[string]string `argo:"long=label"`
+}
+
+func TestConfigFormats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+verbose: true
+network:
+  host: 0.0.0.0
+  port: 8080
+store:
+  data: /var/lib
+tag:
+  - a
+  - b
+label: {team: infra}
+`,
+		"config.toml": `
+verbose = true
+tag = ["a", "b"]
+label = { team = "infra" }
+
+[network]
+host = "0.0.0.0"
+port = 8080
+
+[store]
+data = "/var/lib"
+`,
+	}
+	for name, content := range files {
+		path := writeConfigFile(t, name, content)
+		args := argsConfigGroups{}
+		if err := ParseArgs(&args, []string{}, WithConfigFile(path), WithEnvLookup(noEnv)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !args.Verbose || args.Network.Host != "0.0.0.0" || args.Network.Port != 8080 || args.Storage.Path != "/var/lib" {
+			t.Fatalf("%s: unexpected values: %+v", name, args)
+		}
+		if len(args.Tags) != 2 || args.Tags[1] != "b" || args.Labels["team"] != "infra" {
+			t.Fatalf("%s: unexpected values: %+v", name, args)
+		}
+	}
+}
+
+func TestConfigGroupUnknownKey(t *testing.T) {
+	source := ConfigMap{"network": map[string]interface{}{"data": "/tmp"}}
+	err := ParseArgs(&argsConfigGroups{}, []string{}, WithConfigSource(source), WithEnvLookup(noEnv))
+	var parseErr *ParseError
+	if !errors.Is(err, ErrUnknownConfigKey) || !errors.As(err, &parseErr) || parseErr.Name != "network.data" {
+		t.Fatalf("expected ErrUnknownConfigKey for network.data, got '%v'", err)
+	}
+}
+
+func TestConfigSources(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `port = 9090`)
+	opts := []Option{
+		WithConfigSource(ConfigMap{"addr": "first", "port": 8080, "api_token": "secret"}),
+		WithConfigSource(ConfigMap{"addr": "second"}),
+		WithConfigFile(path),
+		WithEnvLookup(noEnv),
+	}
+
+	args := argsConfig{}
+	if err := ParseArgs(&args, []string{}, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if args.Address != "second" || args.Port != 9090 || args.Token != "secret" {
+		t.Fatalf("unexpected values: %+v", args)
+	}
+
+	path = writeConfigFile(t, "config.conf", `api_token = "secret"`)
+	args = argsConfig{}
+	source := ConfigFileWithDecoder(path, DecodeTOML)
+	if err := ParseArgs(&args, []string{}, WithConfigSource(source), WithEnvLookup(noEnv)); err != nil {
+		t.Fatal(err)
+	}
+	if args.Token != "secret" {
+		t.Fatalf("expected 'secret', got '%s'", args.Token)
+	}
+
+	err := ParseArgs(&argsConfig{}, []string{}, WithConfigSource(ConfigFile(path)), WithEnvLookup(noEnv))
+	var parseErr *ParseError
+	if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &parseErr) || parseErr.Name != path {
+		t.Fatalf("expected ErrInvalidConfig for %s, got '%v'", path, err)
+	}
+}
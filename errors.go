@@ -0,0 +1,85 @@
+package argo
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Source string
+
+const (
+	SourceFlag       Source = "flag"
+	SourcePositional Source = "positional"
+	SourceEnv        Source = "env"
+	SourceConfig     Source = "config"
+	SourceDefault    Source = "default"
+)
+
+type ParseError struct {
+	Err         error
+	Field       string
+	Name        string
+	Value       string
+	Source      Source
+	Cause       error
+	Suggestions []string
+}
+
+func (e *ParseError) Error() string {
+	msg := argoTag + ": "
+	if e.Name != "" {
+		msg += e.Name + ": "
+	} else if e.Field != "" {
+		msg += e.Field + ": "
+	}
+
+	msg += strings.TrimPrefix(e.Err.Error(), argoTag+": ")
+	if e.Value != "" {
+		msg += fmt.Sprintf(" %q", e.Value)
+	}
+	if e.Source != "" && e.Source != SourceFlag && e.Source != SourcePositional {
+		msg += fmt.Sprintf(" from %s", e.Source)
+	}
+	if e.Cause != nil {
+		msg += fmt.Sprintf(": %s", e.Cause)
+	}
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+func newSetError(argument *arg, name string, value string, source Source, cause error) *ParseError {
+	return &ParseError{
+		Err:    ErrCouldNotSet,
+		Field:  argument.name,
+		Name:   name,
+		Value:  value,
+		Source: source,
+		Cause:  cause,
+	}
+}
+
+type ParseErrors struct {
+	Errors []error
+	usage  string
+}
+
+func (e *ParseErrors) Error() string {
+	msg := e.usage
+	for _, err := range e.Errors {
+		msg += fmt.Sprintf("  - %s\n", err)
+	}
+	return msg
+}
+
+func (e *ParseErrors) Unwrap() []error {
+	return e.Errors
+}
@@ -0,0 +1,137 @@
+package argo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type argsErrors struct {
+	Port int    `argo:"short,long,env=PORT"`
+	Name string `argo:"long,required"`
+	File string `argo:"positional,default=a.txt"`
+}
+
+func TestParseErrorCouldNotSet(t *testing.T) {
+	args := argsErrors{}
+	err := ParseArgs(&args, []string{"--port", "abc"})
+	if !errors.Is(err, ErrCouldNotSet) {
+		t.Fatalf("expected ErrCouldNotSet, got '%v'", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected cause to be strconv.ErrSyntax, got '%v'", err)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatal("expected ParseError")
+	}
+	if parseErr.Field != "Port" || parseErr.Name != "--port" || parseErr.Value != "abc" || parseErr.Source != SourceFlag {
+		t.Fatalf("unexpected error details: %+v", parseErr)
+	}
+
+	expected := `argo: --port: could not set value "abc": strconv.ParseInt: parsing "abc": invalid syntax`
+	if err.Error() != expected {
+		t.Fatalf("expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestParseErrorEnv(t *testing.T) {
+	args := argsErrors{}
+	err := ParseArgs(&args, []string{"--name", "x"}, WithEnvLookup(func(string) (string, bool) {
+		return "eighty", true
+	}))
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError, got '%v'", err)
+	}
+	if parseErr.Name != "PORT" || parseErr.Source != SourceEnv || parseErr.Value != "eighty" {
+		t.Fatalf("unexpected error details: %+v", parseErr)
+	}
+}
+
+func TestParseErrorSentinels(t *testing.T) {
+	args := argsErrors{}
+	err := ParseArgs(&args, []string{"-x"})
+	if !errors.Is(err, ErrUnknownArgumentName) {
+		t.Fatalf("expected ErrUnknownArgumentName, got '%v'", err)
+	}
+
+	args = argsErrors{}
+	err = ParseArgs(&args, []string{"-p"})
+	if !errors.Is(err, ErrMissingValue) {
+		t.Fatalf("expected ErrMissingValue, got '%v'", err)
+	}
+
+	args = argsErrors{}
+	err = ParseArgs(&args, []string{}, WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	}))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrRequiredNotSet) {
+		t.Fatalf("expected ErrRequiredNotSet, got '%v'", err)
+	}
+	if parseErr.Field != "Name" || parseErr.Name != "--name" {
+		t.Fatalf("unexpected error details: %+v", parseErr)
+	}
+
+	args2 := argsDuplicates{}
+	err = ParseArgs(&args2, []string{})
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrDuplicateFlagName) {
+		t.Fatalf("expected ErrDuplicateFlagName, got '%v'", err)
+	}
+	if parseErr.Field != "B" || parseErr.Name != "-a" {
+		t.Fatalf("unexpected error details: %+v", parseErr)
+	}
+}
+
+type argsAllErrors struct {
+	Port  int    `argo:"short,long"`
+	Name  string `argo:"long,required"`
+	Token string `argo:"env=TOKEN,required"`
+	File  string `argo:"positional"`
+}
+
+func TestAllErrors(t *testing.T) {
+	args := argsAllErrors{}
+	err := ParseArgs(&args, []string{"--port", "abc", "--unknown"}, WithAllErrors(), WithProgramName("tool"), WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	}))
+
+	var parseErrs *ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("expected ParseErrors, got '%v'", err)
+	}
+	if len(parseErrs.Errors) != 5 {
+		t.Fatalf("expected 5 errors, got %d: %v", len(parseErrs.Errors), err)
+	}
+	for _, sentinel := range []error{ErrCouldNotSet, ErrUnknownArgumentName, ErrRequiredNotSet, ErrPositionalNotSet} {
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("expected '%v' in '%v'", sentinel, err)
+		}
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatal("expected ParseError")
+	}
+	if !strings.HasPrefix(err.Error(), "Usage: ./tool [flags] <File>\n  - argo: ") {
+		t.Fatalf("expected usage line followed by errors, got '%s'", err.Error())
+	}
+
+	args = argsAllErrors{}
+	err = ParseArgs(&args, []string{"--name", "x", "a.txt"}, WithAllErrors(), WithEnvLookup(func(string) (string, bool) {
+		return "secret", true
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args = argsAllErrors{}
+	err = ParseArgs(&args, []string{"--port", "abc", "--unknown"})
+	if errors.As(err, &parseErrs) {
+		t.Fatal("expected a single error without WithAllErrors")
+	}
+}
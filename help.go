@@ -0,0 +1,384 @@
+package argo
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	defaultHelpWidth int = 80
+	helpIndent       int = 2
+	helpColumnGap    int = 2
+	maxHelpFlagWidth int = 32
+	minHelpTextWidth int = 20
+)
+
+const defaultHelpTemplate string = `{{.Usage}}
+{{range .Sections}}{{if .Entries}}
+{{.Title}}:
+{{$.Wrap .Description}}{{$.Rows .Entries}}{{end}}{{end}}`
+
+var defaultHelp = template.Must(template.New("help").Parse(defaultHelpTemplate))
+
+type HelpEntry struct {
+	Name      string
+	Short     string
+	Long      string
+	Env       string
+	Metavar   string
+	Help      string
+	Default   string
+	Required  bool
+	Negatable bool
+	Repeated  bool
+	Group     string
+	Left      string
+	Right     string
+}
+
+type HelpSection struct {
+	Title       string
+	Description string
+	Entries     []HelpEntry
+}
+
+type HelpModel struct {
+	Program     string
+	Command     string
+	Usage       string
+	Positionals []HelpEntry
+	Flags       []HelpEntry
+	GlobalFlags []HelpEntry
+	Commands    []HelpEntry
+	Env         []HelpEntry
+	Sections    []HelpSection
+	Width       int
+	LeftWidth   int
+}
+
+func (r *argsRegistry) helpArgs() []*arg {
+	flags := make([]*arg, 0, len(r.args))
+	groups := make(map[string]int)
+	for _, argument := range r.args {
+		if argument.isPositional {
+			continue
+		}
+		if _, ok := groups[argument.group]; !ok {
+			groups[argument.group] = len(groups)
+		}
+		flags = append(flags, argument)
+	}
+
+	sort.SliceStable(flags, func(i, j int) bool {
+		a, b := flags[i], flags[j]
+		if a.group != b.group {
+			if a.group == "" || b.group == "" {
+				return a.group == ""
+			}
+			return groups[a.group] < groups[b.group]
+		}
+		if a.hasOrder != b.hasOrder {
+			return a.hasOrder
+		}
+		if a.hasOrder && a.order != b.order {
+			return a.order < b.order
+		}
+		if r.parser.sortedHelp {
+			return a.sortKey() < b.sortKey()
+		}
+		return false
+	})
+	return flags
+}
+
+func (p *Parser) builtinArgs() []*arg {
+	args := make([]*arg, 0, 2)
+	if p.helpShort != "" || p.helpLong != "" {
+		args = append(args, &arg{short: p.helpShort, long: p.helpLong, isFlag: true, help: "Print this help message"})
+	}
+	if p.hasVersion {
+		args = append(args, &arg{long: versionLong, isFlag: true, help: "Print the version"})
+	}
+	if p.configFlag != "" {
+		args = append(args, &arg{long: p.configFlag, metavar: "<file>", kind: reflect.String, help: "Read options from a config file"})
+	}
+	return args
+}
+
+func (p *Parser) lookupBuiltin(long string) *arg {
+	for _, argument := range p.builtinArgs() {
+		if argument.long != "" && argument.long == long {
+			return argument
+		}
+	}
+	return nil
+}
+
+func (p *Parser) isHelpFlag(argText string) bool {
+	return (p.helpShort != "" && argText == "-"+p.helpShort) || (p.helpLong != "" && argText == "--"+p.helpLong)
+}
+
+func (r *argsRegistry) helpCommands() []*command {
+	commands := make([]*command, len(r.commandList))
+	copy(commands, r.commandList)
+	if r.parser.sortedHelp {
+		sort.SliceStable(commands, func(i, j int) bool {
+			return commands[i].name < commands[j].name
+		})
+	}
+	return commands
+}
+
+func newHelpEntry(argument *arg) HelpEntry {
+	metavar := argument.metavar
+	if argument.isFlag {
+		metavar = ""
+	}
+
+	return HelpEntry{
+		Name:      argument.name,
+		Short:     argument.short,
+		Long:      argument.long,
+		Env:       argument.env,
+		Metavar:   metavar,
+		Help:      argument.help,
+		Default:   argument.defaultValue,
+		Required:  argument.isRequired,
+		Negatable: argument.isNegatable,
+		Repeated:  argument.isRepeated,
+		Group:     argument.group,
+	}
+}
+
+func formatArgument(argument *arg) HelpEntry {
+	left := ""
+	if argument.short != "" {
+		left += fmt.Sprintf("-%s", argument.short)
+		if argument.long != "" {
+			left += ", "
+		}
+	} else if argument.long != "" {
+		left += "    "
+	}
+
+	if argument.long != "" {
+		left += fmt.Sprintf("--%s", argument.long)
+		if argument.isNegatable {
+			left += fmt.Sprintf(", --%s%s", negationPrefix, argument.long)
+		}
+	}
+
+	hasFlag := left != ""
+	if !hasFlag {
+		left = argument.env
+	}
+	if !argument.isFlag && argument.metavar != "" {
+		left += " " + argument.metavar
+	}
+
+	right := argument.help
+	if hasFlag && argument.env != "" {
+		right += fmt.Sprintf(" [env: %s]", argument.env)
+	}
+
+	entry := newHelpEntry(argument)
+	entry.Left = left
+	entry.Right = strings.TrimSpace(right + argument.helpSuffix())
+	return entry
+}
+
+func formatPositional(argument *arg) HelpEntry {
+	entry := newHelpEntry(argument)
+	entry.Left = fmt.Sprintf("<%s>", argument.name)
+	if argument.isRepeated {
+		entry.Left = fmt.Sprintf("<%s...>", argument.name)
+	}
+	entry.Right = strings.TrimSpace(argument.help + argument.helpSuffix())
+	return entry
+}
+
+func (a *arg) helpSuffix() string {
+	suffix := ""
+	if a.defaultValue != "" {
+		suffix += fmt.Sprintf(" (default: %s)", a.defaultValue)
+	}
+	if a.isRequired {
+		suffix += " (REQUIRED)"
+	}
+	return suffix
+}
+
+func (r *argsRegistry) usage() string {
+	usage := "[flags]"
+	if path := r.path(); path != "" {
+		usage = path + " " + usage
+	}
+	if len(r.commands) > 0 {
+		usage += " <command>"
+	}
+
+	positionals := make([]string, 0, len(r.positional))
+	for _, argument := range r.positional {
+		positionals = append(positionals, formatPositional(argument).Left)
+	}
+	return fmt.Sprintf("Usage: ./%s %s %s\n", r.parser.getProgramName(), usage, strings.Join(positionals, " "))
+}
+
+func (r *argsRegistry) helpModel() *HelpModel {
+	model := &HelpModel{
+		Program: r.parser.getProgramName(),
+		Command: r.path(),
+		Usage:   strings.TrimSuffix(r.usage(), "\n"),
+		Width:   r.parser.helpWidth(),
+	}
+
+	for _, argument := range r.positional {
+		model.Positionals = append(model.Positionals, formatPositional(argument))
+	}
+
+	flags := &HelpSection{Title: "Flags"}
+	groups := make([]*HelpSection, 0)
+	for _, argument := range r.helpArgs() {
+		entry := formatArgument(argument)
+		if argument.short == "" && argument.long == "" {
+			model.Env = append(model.Env, entry)
+			continue
+		}
+		model.Flags = append(model.Flags, entry)
+
+		section := flags
+		if argument.group != "" {
+			if len(groups) == 0 || groups[len(groups)-1].Title != argument.group {
+				groups = append(groups, &HelpSection{Title: argument.group, Description: r.groups[argument.group]})
+			}
+			section = groups[len(groups)-1]
+		}
+		section.Entries = append(section.Entries, entry)
+	}
+	for _, argument := range r.parser.builtinArgs() {
+		flags.Entries = append(flags.Entries, formatArgument(argument))
+	}
+
+	for parent := r.parent; parent != nil; parent = parent.parent {
+		for _, argument := range parent.helpArgs() {
+			if argument.short != "" || argument.long != "" {
+				model.GlobalFlags = append(model.GlobalFlags, formatArgument(argument))
+			}
+		}
+	}
+
+	for _, cmd := range r.helpCommands() {
+		model.Commands = append(model.Commands, HelpEntry{Name: cmd.name, Help: cmd.help, Left: cmd.name, Right: cmd.help})
+	}
+
+	model.Sections = append(model.Sections, HelpSection{Title: "Arguments", Entries: model.Positionals}, *flags)
+	for _, group := range groups {
+		model.Sections = append(model.Sections, *group)
+	}
+	model.Sections = append(model.Sections,
+		HelpSection{Title: "Global flags", Entries: model.GlobalFlags},
+		HelpSection{Title: "Commands", Entries: model.Commands},
+		HelpSection{Title: "Environment variables", Entries: model.Env},
+	)
+
+	for _, section := range model.Sections {
+		for _, entry := range section.Entries {
+			if len(entry.Left) <= maxHelpFlagWidth {
+				model.LeftWidth = max(model.LeftWidth, len(entry.Left))
+			}
+		}
+	}
+	return model
+}
+
+func (r *argsRegistry) help() (string, error) {
+	tmpl := r.parser.helpTemplate
+	if tmpl == nil {
+		tmpl = defaultHelp
+	}
+
+	output := &strings.Builder{}
+	if err := tmpl.Execute(output, r.helpModel()); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+func (m *HelpModel) Rows(entries []HelpEntry) string {
+	textIndent := helpIndent + m.LeftWidth + helpColumnGap
+	textWidth := max(minHelpTextWidth, m.Width-textIndent)
+
+	output := ""
+	for _, entry := range entries {
+		line := strings.Repeat(" ", helpIndent) + entry.Left
+		lines := wrapText(entry.Right, textWidth)
+		if len(lines) == 0 {
+			output += line + "\n"
+			continue
+		}
+
+		if len(entry.Left) > m.LeftWidth {
+			output += line + "\n"
+		} else {
+			output += line + strings.Repeat(" ", textIndent-len(line)) + lines[0] + "\n"
+			lines = lines[1:]
+		}
+		for _, text := range lines {
+			output += strings.Repeat(" ", textIndent) + text + "\n"
+		}
+	}
+	return output
+}
+
+func (m *HelpModel) Wrap(text string) string {
+	output := ""
+	for _, line := range wrapText(text, m.Width-helpIndent) {
+		output += strings.Repeat(" ", helpIndent) + line + "\n"
+	}
+	return output
+}
+
+func wrapText(text string, width int) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (r *argsRegistry) printHelp() error {
+	help, err := r.help()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(r.parser.output, help)
+	return err
+}
+
+func (p *Parser) helpWidth() int {
+	if p.width > 0 {
+		return p.width
+	}
+	if columns, ok := p.lookupEnv("COLUMNS"); ok {
+		if width, err := strconv.Atoi(columns); err == nil && width > 0 {
+			return width
+		}
+	}
+	return defaultHelpWidth
+}
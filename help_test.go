@@ -0,0 +1,255 @@
+package argo
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type argsHelpFormat struct {
+	Address string            `argo:"short=a,long=addr,help=Address to connect to"`
+	Port    int               `argo:"short,long,required,env=PORT,help=Port to listen on which has a long description"`
+	Verbose int               `argo:"short,long,count"`
+	Color   bool              `argo:"long,negatable,default=true"`
+	Labels  map[string]string `argo:"long=label"`
+	Token   string            `argo:"env=APP_TOKEN,help=API token"`
+	Files   []string          `argo:"positional,help=Files to process"`
+}
+
+func TestHelpFormat(t *testing.T) {
+	expected := `Usage: ./tool [flags] <Files...>
+
+Arguments:
+  <Files...>               Files to process
+
+Flags:
+  -a, --addr <string>      Address to connect to
+  -p, --port <int>         Port to listen on which has a long
+                           description [env: PORT] (REQUIRED)
+  -v, --verbose
+      --color, --no-color  (default: true)
+      --label <key=value>
+  -h, --help               Print this help message
+
+Environment variables:
+  APP_TOKEN <string>       API token
+`
+	usage := Usage(&argsHelpFormat{}, WithProgramName("tool"), WithHelpWidth(65))
+	if usage != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, usage)
+	}
+}
+
+func TestHelpWidthFromColumns(t *testing.T) {
+	p := New(WithEnvLookup(func(key string) (string, bool) {
+		if key == "COLUMNS" {
+			return "120", true
+		}
+		return "", false
+	}))
+	if width := p.helpWidth(); width != 120 {
+		t.Fatalf("expected '120', got '%d'", width)
+	}
+
+	p = New(WithHelpWidth(50), WithEnvLookup(func(string) (string, bool) {
+		return "120", true
+	}))
+	if width := p.helpWidth(); width != 50 {
+		t.Fatalf("expected '50', got '%d'", width)
+	}
+
+	p = New(WithEnvLookup(func(string) (string, bool) {
+		return "wide", true
+	}))
+	if width := p.helpWidth(); width != defaultHelpWidth {
+		t.Fatalf("expected '%d', got '%d'", defaultHelpWidth, width)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("one two three four five", 9)
+	if !reflect.DeepEqual(lines, []string{"one two", "three", "four five"}) {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+
+	lines = wrapText("unbreakablewordlongerthanwidth", 5)
+	if !reflect.DeepEqual(lines, []string{"unbreakablewordlongerthanwidth"}) {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+
+	if lines = wrapText("", 10); len(lines) != 0 {
+		t.Fatalf("expected no lines, got %q", lines)
+	}
+}
+
+func TestMetavar(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		metavar string
+	}{
+		{"", "<string>"},
+		{int8(0), "<int>"},
+		{uint(0), "<uint>"},
+		{float32(0), "<float>"},
+		{false, "<bool>"},
+		{struct{}{}, "<value>"},
+	}
+	for _, test := range tests {
+		if result := metavar(reflect.TypeOf(test.value)); result != test.metavar {
+			t.Fatalf("expected '%s', got '%s'", test.metavar, result)
+		}
+	}
+}
+
+type argsNetwork struct {
+	Host string `argo:"long,help=Host to bind"`
+	Port int    `argo:"short,long,default=80"`
+}
+
+type argsStorage struct {
+	Path string `argo:"long=data,env=DATA_PATH"`
+}
+
+type argsGroups struct {
+	Verbose bool        `argo:"short,long"`
+	Network argsNetwork `argo:"group,help=Settings of the network layer"`
+	Storage argsStorage `argo:"group=Storage"`
+	Token   string      `argo:"long,group=Auth"`
+	Cache   string      `argo:"long,group=Storage"`
+}
+
+func TestHelpGroups(t *testing.T) {
+	expected := `Usage: ./tool [flags] 
+
+Flags:
+  -v, --verbose
+  -h, --help            Print this help message
+
+Network:
+  Settings of the network layer
+      --host <string>   Host to bind
+  -p, --port <int>      (default: 80)
+
+Storage:
+      --data <string>   [env: DATA_PATH]
+      --cache <string>
+
+Auth:
+      --token <string>
+`
+	usage := Usage(&argsGroups{}, WithProgramName("tool"))
+	if usage != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, usage)
+	}
+
+	args := argsGroups{}
+	err := ParseArgs(&args, []string{"--host", "0.0.0.0", "--data", "/tmp", "--cache", "mem"}, WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.Network.Host != "0.0.0.0" || args.Network.Port != 80 {
+		t.Fatalf("unexpected network settings: %+v", args.Network)
+	}
+	if args.Storage.Path != "/tmp" || args.Cache != "mem" {
+		t.Fatalf("unexpected storage settings: %+v %s", args.Storage, args.Cache)
+	}
+}
+
+func TestHelpTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("compact").Parse(
+		`{{.Program}}{{range .Flags}} [{{if .Long}}--{{.Long}}{{else}}-{{.Short}}{{end}}{{if .Metavar}} {{.Metavar}}{{end}}]{{end}}{{range .Positionals}} {{.Left}}{{end}}
+{{range .Env}}{{.Env}}{{if .Required}} (required){{end}}
+{{end}}`))
+
+	usage := Usage(&argsHelp{}, WithProgramName("tool"), WithHelpTemplate(tmpl))
+	expected := "tool [--aha] [--b <int>] [-e <string>] <C>\nVAL (required)\n"
+	if usage != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, usage)
+	}
+
+	output := &bytes.Buffer{}
+	args := argsHelp{}
+	err := ParseArgs(&args, []string{"-h"}, WithProgramName("tool"), WithHelpTemplate(tmpl), WithOutput(output))
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if output.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, output.String())
+	}
+}
+
+func TestHelpTemplateError(t *testing.T) {
+	tmpl := template.Must(template.New("broken").Parse(`{{.Missing}}`))
+	if err := PrintHelp(&argsHelp{}, WithHelpTemplate(tmpl)); err == nil {
+		t.Fatal("expected error")
+	}
+	if usage := Usage(&argsHelp{}, WithHelpTemplate(tmpl)); usage != "" {
+		t.Fatalf("expected empty usage, got '%s'", usage)
+	}
+}
+
+type argsHelpHost struct {
+	Host string `argo:"short,long,help=Host to connect to"`
+}
+
+func TestHelpFlagCollision(t *testing.T) {
+	err := ParseArgs(&argsHelpHost{}, []string{})
+	var parseErr *ParseError
+	if !errors.Is(err, ErrDuplicateFlagName) || !errors.As(err, &parseErr) || parseErr.Name != "-h" {
+		t.Fatalf("expected ErrDuplicateFlagName for -h, got '%v'", err)
+	}
+}
+
+func TestHelpFlags(t *testing.T) {
+	output := &bytes.Buffer{}
+	args := argsHelpHost{}
+	opts := []Option{WithProgramName("tool"), WithOutput(output), WithHelpFlags("", "help")}
+	if err := ParseArgs(&args, []string{"-h", "example.com"}, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if args.Host != "example.com" {
+		t.Fatalf("expected 'example.com', got '%s'", args.Host)
+	}
+
+	if err := ParseArgs(&argsHelpHost{}, []string{"--help"}, opts...); !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "  -h, --host <string>  Host to connect to\n      --help           Print this help message\n") {
+		t.Fatalf("unexpected help:\n%s", output.String())
+	}
+
+	positional := argsVersionPositional{}
+	if err := ParseArgs(&positional, []string{"--", "-h"}, WithOutput(output)); err != nil {
+		t.Fatal(err)
+	}
+	if positional.Name != "-h" {
+		t.Fatalf("expected '-h', got '%s'", positional.Name)
+	}
+
+	output.Reset()
+	if err := ParseArgs(&argsHelpHost{}, []string{"-?"}, WithHelpFlags("?", "usage"), WithOutput(output)); !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "  -?, --usage ") {
+		t.Fatalf("unexpected help:\n%s", output.String())
+	}
+
+	if err := ParseArgs(&argsHelpHost{}, []string{}, WithHelpFlags("ab", "")); !errors.Is(err, ErrShortNotSingleChar) {
+		t.Fatalf("expected ErrShortNotSingleChar, got '%v'", err)
+	}
+}
+
+func TestHelpFlagsDisabled(t *testing.T) {
+	opts := []Option{WithProgramName("tool"), WithHelpFlags("", "")}
+	if err := ParseArgs(&argsHelpHost{}, []string{"--help"}, opts...); !errors.Is(err, ErrUnknownArgumentName) {
+		t.Fatalf("expected ErrUnknownArgumentName, got '%v'", err)
+	}
+	if usage := Usage(&argsHelpHost{}, opts...); strings.Contains(usage, "help") {
+		t.Fatalf("expected no help flag, got:\n%s", usage)
+	}
+}
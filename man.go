@@ -0,0 +1,201 @@
+package argo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const defaultManSection string = "1"
+
+type ManMeta struct {
+	Name        string
+	Section     string
+	Date        string
+	Source      string
+	Manual      string
+	Summary     string
+	Description string
+}
+
+func GenerateMan(input interface{}, meta ManMeta) (string, error) {
+	return defaultParser.GenerateMan(input, meta)
+}
+
+func (p *Parser) GenerateMan(input interface{}, meta ManMeta) (string, error) {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return "", err
+	}
+	return argumentsRegistry.man(meta), nil
+}
+
+func (r *argsRegistry) man(meta ManMeta) string {
+	model := r.helpModel()
+	if meta.Name == "" {
+		meta.Name = filepath.Base(model.Program)
+	}
+	if meta.Section == "" {
+		meta.Section = defaultManSection
+	}
+
+	output := fmt.Sprintf(".TH %s %s %s %s %s\n", roffQuote(strings.ToUpper(meta.Name)), roffQuote(meta.Section),
+		roffQuote(meta.Date), roffQuote(meta.Source), roffQuote(meta.Manual))
+
+	output += ".SH NAME\n"
+	output += roffEscape(meta.Name)
+	if meta.Summary != "" {
+		output += ` \- ` + roffEscape(meta.Summary)
+	}
+	output += "\n"
+
+	output += ".SH SYNOPSIS\n"
+	output += fmt.Sprintf(".B %s\n", roffEscape(meta.Name))
+	synopsis := "[\\fIflags\\fR]"
+	if model.Command != "" {
+		synopsis = roffEscape(model.Command) + " " + synopsis
+	}
+	if len(model.Commands) > 0 {
+		synopsis += " \\fI<command>\\fR"
+	}
+	for _, positional := range model.Positionals {
+		synopsis += fmt.Sprintf(" \\fI%s\\fR", roffEscape(positional.Left))
+	}
+	output += synopsis + "\n"
+
+	if meta.Description != "" {
+		output += ".SH DESCRIPTION\n"
+		output += roffParagraphs(meta.Description)
+	}
+
+	if len(model.Positionals) > 0 {
+		output += ".SH ARGUMENTS\n"
+		for _, positional := range model.Positionals {
+			output += manEntry(fmt.Sprintf("\\fI%s\\fR", roffEscape(positional.Left)), positional)
+		}
+	}
+
+	output += ".SH OPTIONS\n"
+	builtins := ""
+	for _, argument := range r.parser.builtinArgs() {
+		entry := newHelpEntry(argument)
+		builtins += manEntry(manFlag(entry), entry)
+	}
+
+	group := ""
+	for _, flag := range model.Flags {
+		if flag.Group != group {
+			if group == "" {
+				output += builtins
+			}
+			group = flag.Group
+			output += fmt.Sprintf(".SS %s\n", roffEscape(group))
+			if description := r.groups[group]; description != "" {
+				output += roffEscape(description) + "\n"
+			}
+		}
+		output += manEntry(manFlag(flag), flag)
+	}
+	if group == "" {
+		output += builtins
+	}
+
+	if len(model.Commands) > 0 {
+		output += ".SH COMMANDS\n"
+		for _, cmd := range model.Commands {
+			output += fmt.Sprintf(".TP\n\\fB%s\\fR\n", roffEscape(cmd.Name))
+			if cmd.Help != "" {
+				output += roffEscape(cmd.Help) + "\n"
+			}
+		}
+	}
+
+	envs := make([]HelpEntry, 0)
+	for _, entry := range append(model.Flags, model.Env...) {
+		if entry.Env != "" {
+			envs = append(envs, entry)
+		}
+	}
+	if len(envs) > 0 {
+		output += ".SH ENVIRONMENT\n"
+		for _, env := range envs {
+			description := env
+			if env.Short != "" || env.Long != "" {
+				description.Help = strings.TrimSpace(fmt.Sprintf("Same as %s. %s", manFlagName(env), env.Help))
+			}
+			output += manEntry(fmt.Sprintf("\\fB%s\\fR", roffEscape(env.Env)), description)
+		}
+	}
+
+	return output
+}
+
+func manFlagName(entry HelpEntry) string {
+	if entry.Long != "" {
+		return "--" + entry.Long
+	}
+	return "-" + entry.Short
+}
+
+func manFlag(entry HelpEntry) string {
+	names := make([]string, 0, 3)
+	if entry.Short != "" {
+		names = append(names, fmt.Sprintf("\\fB%s\\fR", roffEscape("-"+entry.Short)))
+	}
+	if entry.Long != "" {
+		names = append(names, fmt.Sprintf("\\fB%s\\fR", roffEscape("--"+entry.Long)))
+		if entry.Negatable {
+			names = append(names, fmt.Sprintf("\\fB%s\\fR", roffEscape("--"+negationPrefix+entry.Long)))
+		}
+	}
+
+	flag := strings.Join(names, ", ")
+	if entry.Metavar != "" {
+		flag += fmt.Sprintf(" \\fI%s\\fR", roffEscape(entry.Metavar))
+	}
+	return flag
+}
+
+func manEntry(term string, entry HelpEntry) string {
+	output := fmt.Sprintf(".TP\n%s\n", term)
+
+	description := entry.Help
+	if entry.Default != "" {
+		description += fmt.Sprintf(" (default: %s)", entry.Default)
+	}
+	if entry.Required {
+		description += " (required)"
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		output += roffEscape(description) + "\n"
+	}
+	return output
+}
+
+func roffParagraphs(text string) string {
+	output := ""
+	for i, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if i > 0 {
+			output += ".PP\n"
+		}
+		output += roffEscape(strings.TrimSpace(paragraph)) + "\n"
+	}
+	return output
+}
+
+func roffQuote(text string) string {
+	return `"` + strings.ReplaceAll(roffEscape(text), `"`, `\(dq`) + `"`
+}
+
+func roffEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
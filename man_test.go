@@ -0,0 +1,102 @@
+package argo
+
+import (
+	"strings"
+	"testing"
+)
+
+type argsMan struct {
+	Port    int      `argo:"short,long,required,env=PORT,help=Port to listen on"`
+	Color   bool     `argo:"long,negatable,default=true"`
+	Token   string   `argo:"env=APP_TOKEN,help=API token"`
+	Sources []string `argo:"positional,help=Files to read"`
+}
+
+func TestGenerateMan(t *testing.T) {
+	expected := `.TH "TOOL" "1" "2024\-01\-01" "tool 1.0" "User Commands"
+.SH NAME
+tool \- serve files
+.SH SYNOPSIS
+.B tool
+[\fIflags\fR] \fI<Sources...>\fR
+.SH DESCRIPTION
+Serves files over HTTP.
+.PP
+\&.dotfiles are skipped.
+.SH ARGUMENTS
+.TP
+\fI<Sources...>\fR
+Files to read
+.SH OPTIONS
+.TP
+\fB\-p\fR, \fB\-\-port\fR \fI<int>\fR
+Port to listen on (required)
+.TP
+\fB\-\-color\fR, \fB\-\-no\-color\fR
+(default: true)
+.TP
+\fB\-h\fR, \fB\-\-help\fR
+Print this help message
+.SH ENVIRONMENT
+.TP
+\fBPORT\fR
+Same as \-\-port. Port to listen on (required)
+.TP
+\fBAPP_TOKEN\fR
+API token
+`
+	man, err := GenerateMan(&argsMan{}, ManMeta{
+		Name:        "tool",
+		Date:        "2024-01-01",
+		Source:      "tool 1.0",
+		Manual:      "User Commands",
+		Summary:     "serve files",
+		Description: "Serves files over HTTP.\n\n.dotfiles are skipped.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if man != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, man)
+	}
+}
+
+func TestGenerateManCommands(t *testing.T) {
+	man, err := GenerateMan(&argsCommands{}, ManMeta{Name: "tool", Section: "8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(man, `.TH "TOOL" "8"`) {
+		t.Fatalf("expected section 8, got '%s'", man)
+	}
+	if !strings.Contains(man, ".SH COMMANDS\n.TP\n\\fBdeploy\\fR\nDeploy the service\n.TP\n\\fBrollback\\fR\n") {
+		t.Fatalf("expected commands, got '%s'", man)
+	}
+
+	man, err = New(WithProgramName("./bin/tool")).GenerateMan(&argsCommands{}, ManMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(man, `.TH "TOOL" "1"`) || !strings.Contains(man, ".SH NAME\ntool\n.SH SYNOPSIS\n.B tool\n") {
+		t.Fatalf("expected the base program name, got '%s'", man)
+	}
+
+	if _, err := GenerateMan(argsCommands{}, ManMeta{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := map[string]string{
+		"--port":      `\-\-port`,
+		`C:\path`:     `C:\epath`,
+		".start":      `\&.start`,
+		"a\n'quoted":  "a\n\\&'quoted",
+		"plain text.": "plain text.",
+	}
+	for input, expected := range tests {
+		if result := roffEscape(input); result != expected {
+			t.Fatalf("expected '%s', got '%s'", expected, result)
+		}
+	}
+}
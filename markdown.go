@@ -0,0 +1,176 @@
+package argo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const maxMarkdownHeading int = 6
+
+type MarkdownMeta struct {
+	Name        string
+	Description string
+}
+
+func GenerateMarkdown(input interface{}, meta MarkdownMeta) (string, error) {
+	return defaultParser.GenerateMarkdown(input, meta)
+}
+
+func (p *Parser) GenerateMarkdown(input interface{}, meta MarkdownMeta) (string, error) {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return "", err
+	}
+	if meta.Name == "" {
+		meta.Name = filepath.Base(p.getProgramName())
+	}
+	return argumentsRegistry.markdown(meta, 1), nil
+}
+
+func (r *argsRegistry) markdown(meta MarkdownMeta, level int) string {
+	model := r.helpModel()
+	name := strings.TrimSpace(meta.Name + " " + model.Command)
+
+	output := markdownHeading(level, name)
+	if meta.Description != "" {
+		output += strings.TrimSpace(meta.Description) + "\n\n"
+	}
+
+	synopsis := name + " [flags]"
+	if len(model.Commands) > 0 {
+		synopsis += " <command>"
+	}
+	for _, positional := range model.Positionals {
+		synopsis += " " + positional.Left
+	}
+	output += fmt.Sprintf("```\n%s\n```\n\n", synopsis)
+
+	if len(model.Positionals) > 0 {
+		output += markdownHeading(level+1, "Arguments")
+		output += markdownTable([]string{"Argument", "Type", "Default", "Required", "Description"}, model.Positionals, func(entry HelpEntry) []string {
+			return []string{markdownCode(entry.Left), markdownCode(entry.Metavar), markdownCode(entry.Default), markdownRequired(entry), entry.Help}
+		})
+	}
+
+	flagColumns := []string{"Flag", "Type", "Environment", "Default", "Required", "Description"}
+	flagRow := func(entry HelpEntry) []string {
+		return []string{markdownCode(markdownFlag(entry)), markdownCode(entry.Metavar), markdownCode(entry.Env), markdownCode(entry.Default), markdownRequired(entry), entry.Help}
+	}
+
+	for _, section := range model.Sections {
+		if section.Title == "Flags" {
+			output += markdownHeading(level+1, section.Title)
+			output += markdownTable(flagColumns, section.Entries, flagRow)
+			continue
+		}
+		if len(section.Entries) == 0 || section.Entries[0].Group == "" {
+			continue
+		}
+		output += markdownHeading(level+1, section.Title)
+		if section.Description != "" {
+			output += section.Description + "\n\n"
+		}
+		output += markdownTable(flagColumns, section.Entries, flagRow)
+	}
+
+	envs := make([]HelpEntry, 0)
+	for _, entry := range model.Positionals {
+		if entry.Env != "" {
+			envs = append(envs, entry)
+		}
+	}
+	for _, entry := range append(model.Flags, model.Env...) {
+		if entry.Env != "" {
+			entry.Left = markdownFlag(entry)
+			envs = append(envs, entry)
+		}
+	}
+	if len(envs) > 0 {
+		output += markdownHeading(level+1, "Environment variables")
+		output += markdownTable([]string{"Variable", "Argument", "Type", "Default", "Required", "Description"}, envs, func(entry HelpEntry) []string {
+			return []string{markdownCode(entry.Env), markdownCode(entry.Left), markdownCode(entry.Metavar), markdownCode(entry.Default), markdownRequired(entry), entry.Help}
+		})
+	}
+
+	commands := r.helpCommands()
+	if len(commands) > 0 {
+		output += markdownHeading(level+1, "Commands")
+		output += markdownTable([]string{"Command", "Description"}, model.Commands, func(entry HelpEntry) []string {
+			return []string{fmt.Sprintf("[%s](#%s)", entry.Name, markdownAnchor(name+" "+entry.Name)), entry.Help}
+		})
+	}
+
+	for _, cmd := range commands {
+		output += cmd.registry.markdown(MarkdownMeta{Name: meta.Name, Description: cmd.help}, level+1)
+	}
+	return output
+}
+
+func markdownHeading(level int, title string) string {
+	return fmt.Sprintf("%s %s\n\n", strings.Repeat("#", min(level, maxMarkdownHeading)), title)
+}
+
+func markdownTable(columns []string, entries []HelpEntry, row func(HelpEntry) []string) string {
+	separators := make([]string, len(columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+
+	output := "| " + strings.Join(columns, " | ") + " |\n"
+	output += "| " + strings.Join(separators, " | ") + " |\n"
+	for _, entry := range entries {
+		cells := row(entry)
+		for i, cell := range cells {
+			cells[i] = markdownCell(cell)
+		}
+		output += "| " + strings.Join(cells, " | ") + " |\n"
+	}
+	return output + "\n"
+}
+
+func markdownFlag(entry HelpEntry) string {
+	names := make([]string, 0, 3)
+	if entry.Short != "" {
+		names = append(names, "-"+entry.Short)
+	}
+	if entry.Long != "" {
+		names = append(names, "--"+entry.Long)
+		if entry.Negatable {
+			names = append(names, "--"+negationPrefix+entry.Long)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func markdownRequired(entry HelpEntry) string {
+	if entry.Required {
+		return "yes"
+	}
+	return ""
+}
+
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + text + "`"
+}
+
+func markdownCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+func markdownAnchor(title string) string {
+	anchor := ""
+	for _, c := range strings.ToLower(title) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+			anchor += string(c)
+		case c == ' ':
+			anchor += "-"
+		}
+	}
+	return anchor
+}
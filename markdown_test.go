@@ -0,0 +1,80 @@
+package argo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMarkdown(t *testing.T) {
+	expected := "# tool\n\nServes files.\n\n```\ntool [flags] <Sources...>\n```\n\n" +
+		"## Arguments\n\n" +
+		"| Argument | Type | Default | Required | Description |\n| --- | --- | --- | --- | --- |\n" +
+		"| `<Sources...>` | `<string>` |  |  | Files to read |\n\n" +
+		"## Flags\n\n" +
+		"| Flag | Type | Environment | Default | Required | Description |\n| --- | --- | --- | --- | --- | --- |\n" +
+		"| `-p, --port` | `<int>` | `PORT` |  | yes | Port to listen on |\n" +
+		"| `--color, --no-color` |  |  | `true` |  |  |\n" +
+		"| `-h, --help` |  |  |  |  | Print this help message |\n\n" +
+		"## Environment variables\n\n" +
+		"| Variable | Argument | Type | Default | Required | Description |\n| --- | --- | --- | --- | --- | --- |\n" +
+		"| `PORT` | `-p, --port` | `<int>` |  | yes | Port to listen on |\n" +
+		"| `APP_TOKEN` |  | `<string>` |  |  | API token |\n\n"
+
+	markdown, err := GenerateMarkdown(&argsMan{}, MarkdownMeta{Name: "tool", Description: "Serves files."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if markdown != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, markdown)
+	}
+}
+
+func TestGenerateMarkdownCommands(t *testing.T) {
+	markdown, err := New(WithProgramName("tool")).GenerateMarkdown(&argsCommands{}, MarkdownMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"| [deploy](#tool-deploy) | Deploy the service |\n",
+		"## tool deploy\n\nDeploy the service\n\n```\ntool deploy [flags]\n```\n\n### Flags\n",
+		"| `-e, --env` | `<string>` |  |  | yes |  |\n",
+		"## tool rollback\n\n```\ntool rollback [flags] <ID>\n```\n\n### Arguments\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Fatalf("expected '%s' in:\n%s", expected, markdown)
+		}
+	}
+}
+
+func TestGenerateMarkdownProgramPath(t *testing.T) {
+	markdown, err := New(WithProgramName("./bin/tool")).GenerateMarkdown(&argsCommands{}, MarkdownMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(markdown, "# tool\n") || !strings.Contains(markdown, "| [deploy](#tool-deploy) |") {
+		t.Fatalf("expected the base program name in:\n%s", markdown)
+	}
+}
+
+func TestGenerateMarkdownGroups(t *testing.T) {
+	markdown, err := GenerateMarkdown(&argsGroups{}, MarkdownMeta{Name: "tool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown, "## Network\n\nSettings of the network layer\n\n| Flag |") {
+		t.Fatalf("expected network section in:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "| `DATA_PATH` | `--data` | `<string>` |") {
+		t.Fatalf("expected environment variable in:\n%s", markdown)
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if cell := markdownCell("a | b\n  c"); cell != `a \| b c` {
+		t.Fatalf("unexpected cell '%s'", cell)
+	}
+	if anchor := markdownAnchor("tool deploy-now"); anchor != "tool-deploy-now" {
+		t.Fatalf("unexpected anchor '%s'", anchor)
+	}
+}
@@ -0,0 +1,270 @@
+package argo
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"text/template"
+)
+
+type Parser struct {
+	setters       map[reflect.Kind]setterFunc
+	args          []string
+	programName   string
+	lookupEnv     func(string) (string, bool)
+	output        io.Writer
+	separator     string
+	allErrors     bool
+	envPrefix     string
+	environ       func() []string
+	sortedHelp    bool
+	width         int
+	helpTemplate  *template.Template
+	completions   map[string]CompletionFunc
+	stdout        io.Writer
+	version       string
+	hasVersion    bool
+	helpShort     string
+	helpLong      string
+	configFile    string
+	configFlag    string
+	configSources []ConfigSource
+}
+
+type Option func(*Parser)
+
+const defaultSeparator string = ";"
+
+var defaultParser = New()
+
+func New(opts ...Option) *Parser {
+	p := &Parser{
+		setters:   make(map[reflect.Kind]setterFunc, len(setters)),
+		lookupEnv: os.LookupEnv,
+		separator: defaultSeparator,
+		environ:   os.Environ,
+		output:    os.Stderr,
+		stdout:    os.Stdout,
+		helpShort: defaultHelpShort,
+		helpLong:  defaultHelpLong,
+	}
+	for kind, setter := range setters {
+		p.setters[kind] = setter
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func WithProgramName(name string) Option {
+	return func(p *Parser) {
+		p.programName = name
+	}
+}
+
+func WithArgs(args []string) Option {
+	return func(p *Parser) {
+		p.args = args
+	}
+}
+
+func WithEnvLookup(lookupEnv func(string) (string, bool)) Option {
+	return func(p *Parser) {
+		p.lookupEnv = lookupEnv
+	}
+}
+
+func WithOutput(w io.Writer) Option {
+	return func(p *Parser) {
+		p.output = w
+	}
+}
+
+func WithStdout(w io.Writer) Option {
+	return func(p *Parser) {
+		p.stdout = w
+	}
+}
+
+func WithSeparator(separator string) Option {
+	return func(p *Parser) {
+		p.separator = separator
+	}
+}
+
+func WithAllErrors() Option {
+	return func(p *Parser) {
+		p.allErrors = true
+	}
+}
+
+func WithStrictEnv(prefix string) Option {
+	return func(p *Parser) {
+		p.envPrefix = prefix
+	}
+}
+
+func WithEnviron(environ func() []string) Option {
+	return func(p *Parser) {
+		p.environ = environ
+	}
+}
+
+func WithSortedHelp() Option {
+	return func(p *Parser) {
+		p.sortedHelp = true
+	}
+}
+
+func WithHelpWidth(width int) Option {
+	return func(p *Parser) {
+		p.width = width
+	}
+}
+
+func WithHelpTemplate(tmpl *template.Template) Option {
+	return func(p *Parser) {
+		p.helpTemplate = tmpl
+	}
+}
+
+func WithCompletion(name string, completion CompletionFunc) Option {
+	return func(p *Parser) {
+		if p.completions == nil {
+			p.completions = make(map[string]CompletionFunc)
+		}
+		p.completions[name] = completion
+	}
+}
+
+func WithHelpFlags(short, long string) Option {
+	return func(p *Parser) {
+		p.helpShort = short
+		p.helpLong = long
+	}
+}
+
+func WithConfigFile(path string) Option {
+	return func(p *Parser) {
+		p.configFile = path
+	}
+}
+
+func WithConfigSource(source ConfigSource) Option {
+	return func(p *Parser) {
+		p.configSources = append(p.configSources[:len(p.configSources):len(p.configSources)], source)
+	}
+}
+
+func WithConfigFlag(long string) Option {
+	return func(p *Parser) {
+		p.configFlag = long
+	}
+}
+
+func WithVersion(version string) Option {
+	return func(p *Parser) {
+		p.version = version
+		p.hasVersion = true
+	}
+}
+
+func WithSetter(t interface{}, setter setterFunc) Option {
+	return func(p *Parser) {
+		p.setters[reflect.TypeOf(t).Kind()] = setter
+	}
+}
+
+func (p *Parser) with(opts []Option) *Parser {
+	if len(opts) == 0 {
+		return p
+	}
+
+	clone := *p
+	clone.setters = make(map[reflect.Kind]setterFunc, len(p.setters))
+	for kind, setter := range p.setters {
+		clone.setters[kind] = setter
+	}
+	if p.completions != nil {
+		clone.completions = make(map[string]CompletionFunc, len(p.completions))
+		for name, completion := range p.completions {
+			clone.completions[name] = completion
+		}
+	}
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	return &clone
+}
+
+func (p *Parser) RegisterSetter(t interface{}, setter setterFunc) error {
+	kind := reflect.TypeOf(t).Kind()
+	if _, ok := p.setters[kind]; ok {
+		return ErrSetterAlreadyExists
+	}
+	p.setters[kind] = setter
+	return nil
+}
+
+func (p *Parser) getProgramName() string {
+	if p.programName != "" || len(os.Args) == 0 {
+		return p.programName
+	}
+	return os.Args[0]
+}
+
+func (p *Parser) getArgs() []string {
+	if p.args != nil || len(os.Args) == 0 {
+		return p.args
+	}
+	return os.Args[1:]
+}
+
+func (p *Parser) Parse(input interface{}) error {
+	return p.ParseArgs(input, p.getArgs())
+}
+
+func (p *Parser) ParseArgs(input interface{}, args []string) error {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return err
+	}
+
+	if err = argumentsRegistry.parseInput(args); err != nil {
+		return err
+	}
+
+	if err = argumentsRegistry.loadConfig(); err != nil {
+		return err
+	}
+
+	if err = validateEnviron(argumentsRegistry); err != nil {
+		return err
+	}
+
+	if err = validateArgsRegistry(argumentsRegistry); err != nil {
+		return err
+	}
+	return argumentsRegistry.collectedErrors()
+}
+
+func (p *Parser) PrintHelp(input interface{}) error {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return err
+	}
+	return argumentsRegistry.printHelp()
+}
+
+func (p *Parser) Usage(input interface{}) string {
+	argumentsRegistry, err := p.interfaceToArgsRegistry(input)
+	if err != nil {
+		return ""
+	}
+	help, err := argumentsRegistry.help()
+	if err != nil {
+		return ""
+	}
+	return help
+}
@@ -0,0 +1,106 @@
+package argo
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type argsParserEnv struct {
+	Host string `argo:"env=HOST"`
+	Port int    `argo:"short,long,env=PORT"`
+}
+
+func TestParserEnvLookup(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{"HOST": "example.com", "PORT": "8080"}
+	p := New(WithArgs([]string{}), WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+
+	args := argsParserEnv{}
+	if err := p.Parse(&args); err != nil {
+		t.Fatal(err)
+	}
+	if args.Host != "example.com" {
+		t.Fatalf("expected 'example.com', got '%s'", args.Host)
+	}
+	if args.Port != 8080 {
+		t.Fatalf("expected '8080', got '%d'", args.Port)
+	}
+}
+
+func TestParserArgs(t *testing.T) {
+	t.Parallel()
+	p := New(WithArgs([]string{"-a", "localhost"}))
+
+	args := argsSimple{}
+	if err := p.Parse(&args); err != nil {
+		t.Fatal(err)
+	}
+	if args.Address != "localhost" {
+		t.Fatalf("expected 'localhost', got '%s'", args.Address)
+	}
+}
+
+type parserCustomType struct {
+	Value string
+}
+
+type argsParserCustom struct {
+	A parserCustomType `argo:"short=a"`
+}
+
+func TestParserSetters(t *testing.T) {
+	t.Parallel()
+	upper := func(s string, value reflect.Value) error {
+		value.Field(0).SetString(strings.ToUpper(s))
+		return nil
+	}
+	lower := func(s string, value reflect.Value) error {
+		value.Field(0).SetString(strings.ToLower(s))
+		return nil
+	}
+
+	p1 := New()
+	if err := p1.RegisterSetter(parserCustomType{}, upper); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.RegisterSetter(parserCustomType{}, upper); err == nil {
+		t.Fatal("expected error")
+	}
+	p2 := New(WithSetter(parserCustomType{}, lower))
+
+	args := argsParserCustom{}
+	if err := p1.ParseArgs(&args, []string{"-a", "Value"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.A.Value != "VALUE" {
+		t.Fatalf("expected 'VALUE', got '%s'", args.A.Value)
+	}
+
+	args = argsParserCustom{}
+	if err := p2.ParseArgs(&args, []string{"-a", "Value"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.A.Value != "value" {
+		t.Fatalf("expected 'value', got '%s'", args.A.Value)
+	}
+}
+
+func TestParserOutput(t *testing.T) {
+	t.Parallel()
+	output := &bytes.Buffer{}
+	p := New(WithProgramName("tool"), WithOutput(output))
+
+	args := argsSimple{}
+	if err := p.ParseArgs(&args, []string{"-h"}); !errors.Is(err, ErrHelp) {
+		t.Fatalf("expected ErrHelp, got '%v'", err)
+	}
+	if !strings.Contains(output.String(), "Usage: ./tool [flags]") {
+		t.Fatalf("expected help text, got '%s'", output.String())
+	}
+}
@@ -0,0 +1,125 @@
+package argo
+
+import (
+	"sort"
+	"strings"
+)
+
+const maxSuggestions int = 3
+
+func editDistance(a string, b string) int {
+	distances := make([][]int, len(a)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(b)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			distances[i][j] = min(distances[i-1][j]+1, distances[i][j-1]+1, distances[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				distances[i][j] = min(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+	return distances[len(a)][len(b)]
+}
+
+func suggest(name string, candidates []string) []string {
+	name = strings.ToLower(name)
+	maxDistance := max(1, min(2, len(name)/3))
+	distances := make(map[string]int)
+	for _, candidate := range candidates {
+		lowerCandidate := strings.ToLower(candidate)
+		distance := editDistance(name, lowerCandidate)
+		isPrefix := len(name) > 2 && strings.HasPrefix(lowerCandidate, name)
+		if distance <= maxDistance || isPrefix {
+			distances[candidate] = distance
+		}
+	}
+
+	suggestions := make([]string, 0, len(distances))
+	for candidate := range distances {
+		suggestions = append(suggestions, candidate)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+func (r *argsRegistry) suggestLong(name string) []string {
+	candidates := make([]string, 0)
+	for registry := r; registry != nil; registry = registry.parent {
+		for long := range registry.long {
+			candidates = append(candidates, long)
+		}
+	}
+
+	suggestions := suggest(name, candidates)
+	for i, suggestion := range suggestions {
+		suggestions[i] = "--" + suggestion
+	}
+	return suggestions
+}
+
+func (r *argsRegistry) suggestCommand(name string) []string {
+	candidates := make([]string, 0, len(r.commands))
+	for commandName := range r.commands {
+		candidates = append(candidates, commandName)
+	}
+	return suggest(name, candidates)
+}
+
+func (r *argsRegistry) envNames() []string {
+	names := make([]string, 0, len(r.env))
+	for env := range r.env {
+		names = append(names, env)
+	}
+	for _, cmd := range r.commands {
+		names = append(names, cmd.registry.envNames()...)
+	}
+	return names
+}
+
+func validateEnviron(r *argsRegistry) error {
+	if r.parser.envPrefix == "" {
+		return nil
+	}
+
+	known := r.envNames()
+	knownSet := make(map[string]struct{}, len(known))
+	for _, env := range known {
+		knownSet[env] = struct{}{}
+	}
+
+	for _, entry := range r.parser.environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, r.parser.envPrefix) {
+			continue
+		}
+		if _, ok := knownSet[name]; ok {
+			continue
+		}
+
+		err := &ParseError{Err: ErrUnknownEnv, Name: name, Source: SourceEnv, Suggestions: suggest(name, known)}
+		if err := r.fail(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
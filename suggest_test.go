@@ -0,0 +1,114 @@
+package argo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"port", "port", 0},
+		{"prot", "port", 1},
+		{"prt", "port", 1},
+		{"verbos", "verbose", 1},
+		{"host", "port", 2},
+		{"", "abc", 3},
+	}
+	for _, test := range tests {
+		if distance := editDistance(test.a, test.b); distance != test.distance {
+			t.Fatalf("expected distance between '%s' and '%s' to be %d, got %d", test.a, test.b, test.distance, distance)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"port", "print", "host", "verbose"}
+	if suggestions := suggest("prot", candidates); !reflect.DeepEqual(suggestions, []string{"port"}) {
+		t.Fatalf("expected '[port]', got '%v'", suggestions)
+	}
+	if suggestions := suggest("verb", candidates); !reflect.DeepEqual(suggestions, []string{"verbose"}) {
+		t.Fatalf("expected '[verbose]', got '%v'", suggestions)
+	}
+	if suggestions := suggest("xyz", candidates); len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got '%v'", suggestions)
+	}
+}
+
+type argsSuggest struct {
+	Port    int  `argo:"short,long"`
+	Verbose bool `argo:"long"`
+}
+
+func TestSuggestUnknownFlag(t *testing.T) {
+	args := argsSuggest{}
+	err := ParseArgs(&args, []string{"--prot", "80"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrUnknownArgumentName) {
+		t.Fatalf("expected ErrUnknownArgumentName, got '%v'", err)
+	}
+	if !reflect.DeepEqual(parseErr.Suggestions, []string{"--port"}) {
+		t.Fatalf("expected '[--port]', got '%v'", parseErr.Suggestions)
+	}
+	if !strings.HasSuffix(err.Error(), "(did you mean --port?)") {
+		t.Fatalf("expected suggestion in message, got '%s'", err.Error())
+	}
+
+	args = argsSuggest{}
+	err = ParseArgs(&args, []string{"-verbose"})
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError, got '%v'", err)
+	}
+	if !reflect.DeepEqual(parseErr.Suggestions, []string{"--verbose"}) {
+		t.Fatalf("expected '[--verbose]', got '%v'", parseErr.Suggestions)
+	}
+}
+
+func TestSuggestUnknownCommand(t *testing.T) {
+	args := argsCommands{}
+	err := ParseArgs(&args, []string{"deplyo"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("expected ErrUnknownCommand, got '%v'", err)
+	}
+	if !reflect.DeepEqual(parseErr.Suggestions, []string{"deploy"}) {
+		t.Fatalf("expected '[deploy]', got '%v'", parseErr.Suggestions)
+	}
+}
+
+type argsStrictEnv struct {
+	Host string `argo:"env=APP_HOST"`
+	Port int    `argo:"env=APP_PORT"`
+}
+
+func TestStrictEnv(t *testing.T) {
+	environ := []string{"APP_HOST=localhost", "APP_PROT=80", "HOME=/root"}
+	opts := []Option{
+		WithEnviron(func() []string {
+			return environ
+		}),
+		WithEnvLookup(func(string) (string, bool) {
+			return "", false
+		}),
+	}
+
+	args := argsStrictEnv{}
+	if err := ParseArgs(&args, []string{}, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	args = argsStrictEnv{}
+	err := ParseArgs(&args, []string{}, append(opts, WithStrictEnv("APP_"))...)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrUnknownEnv) {
+		t.Fatalf("expected ErrUnknownEnv, got '%v'", err)
+	}
+	if parseErr.Name != "APP_PROT" || !reflect.DeepEqual(parseErr.Suggestions, []string{"APP_PORT"}) {
+		t.Fatalf("unexpected error details: %+v", parseErr)
+	}
+}
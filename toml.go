@@ -0,0 +1,433 @@
+package argo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tomlParser struct {
+	text    string
+	pos     int
+	line    int
+	root    map[string]interface{}
+	tables  map[string]bool
+	current map[string]interface{}
+}
+
+func DecodeTOML(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	p := &tomlParser{
+		text:    strings.ReplaceAll(string(data), "\r\n", "\n"),
+		line:    1,
+		root:    root,
+		tables:  make(map[string]bool),
+		current: root,
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("toml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank(true)
+		if p.pos == len(p.text) {
+			return nil
+		}
+
+		if p.text[p.pos] == '[' {
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		} else if err := p.parseKeyValue(p.current); err != nil {
+			return err
+		}
+
+		p.skipBlank(false)
+		if p.pos < len(p.text) && p.text[p.pos] != '\n' {
+			return p.errorf("expected a new line, got %q", p.text[p.pos])
+		}
+	}
+}
+
+func (p *tomlParser) skipBlank(newlines bool) {
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.text) && p.text[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) parseTable() error {
+	p.pos++
+	if p.pos < len(p.text) && p.text[p.pos] == '[' {
+		return p.errorf("arrays of tables are not supported")
+	}
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.skipBlank(false); p.pos == len(p.text) || p.text[p.pos] != ']' {
+		return p.errorf("expected ']' after table name")
+	}
+	p.pos++
+
+	name := strings.Join(keys, configKeySeparator)
+	if p.tables[name] {
+		return p.errorf("duplicate table %q", name)
+	}
+	p.tables[name] = true
+
+	table, err := p.table(p.root, keys)
+	if err != nil {
+		return err
+	}
+	p.current = table
+	return nil
+}
+
+func (p *tomlParser) table(parent map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch value := parent[key].(type) {
+		case nil:
+			table := make(map[string]interface{})
+			parent[key] = table
+			parent = table
+		case map[string]interface{}:
+			parent = value
+		default:
+			return nil, p.errorf("key %q is not a table", key)
+		}
+	}
+	return parent, nil
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.skipBlank(false); p.pos == len(p.text) || p.text[p.pos] != '=' {
+		return p.errorf("expected '=' after key %q", strings.Join(keys, configKeySeparator))
+	}
+	p.pos++
+
+	parent, err := p.table(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	key := keys[len(keys)-1]
+	if _, ok := parent[key]; ok {
+		return p.errorf("duplicate key %q", strings.Join(keys, configKeySeparator))
+	}
+
+	p.skipBlank(false)
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent[key] = value
+	return nil
+}
+
+func (p *tomlParser) parseKey() ([]string, error) {
+	keys := make([]string, 0, 1)
+	for {
+		p.skipBlank(false)
+		if p.pos == len(p.text) {
+			return nil, p.errorf("expected a key")
+		}
+
+		switch p.text[p.pos] {
+		case '"':
+			key, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		case '\'':
+			key, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		default:
+			start := p.pos
+			for p.pos < len(p.text) && isTOMLBareKeyChar(p.text[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key character %q", p.text[p.pos])
+			}
+			keys = append(keys, p.text[start:p.pos])
+		}
+
+		if p.skipBlank(false); p.pos == len(p.text) || p.text[p.pos] != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseValue() (interface{}, error) {
+	if p.pos == len(p.text) {
+		return nil, p.errorf("expected a value")
+	}
+	switch {
+	case strings.HasPrefix(p.text[p.pos:], `"""`):
+		return p.parseMultilineString(`"""`)
+	case strings.HasPrefix(p.text[p.pos:], `'''`):
+		return p.parseMultilineString(`'''`)
+	case p.text[p.pos] == '"':
+		return p.parseBasicString()
+	case p.text[p.pos] == '\'':
+		return p.parseLiteralString()
+	case p.text[p.pos] == '[':
+		return p.parseArray()
+	case p.text[p.pos] == '{':
+		return p.parseInlineTable()
+	default:
+		return p.parseBare()
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var builder strings.Builder
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; c {
+		case '"':
+			p.pos++
+			return builder.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			if err := p.parseEscape(&builder, false); err != nil {
+				return "", err
+			}
+		default:
+			builder.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.text[p.pos:], "'\n")
+	if end < 0 || p.text[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	value := p.text[p.pos : p.pos+end]
+	p.pos += end + 1
+	return value, nil
+}
+
+func (p *tomlParser) parseMultilineString(delimiter string) (string, error) {
+	p.pos += len(delimiter)
+	if strings.HasPrefix(p.text[p.pos:], "\n") {
+		p.pos++
+		p.line++
+	}
+
+	var builder strings.Builder
+	for p.pos < len(p.text) {
+		if strings.HasPrefix(p.text[p.pos:], delimiter) {
+			p.pos += len(delimiter)
+			for i := 0; i < 2 && p.pos < len(p.text) && p.text[p.pos] == delimiter[0]; i++ {
+				builder.WriteByte(delimiter[0])
+				p.pos++
+			}
+			return builder.String(), nil
+		}
+
+		c := p.text[p.pos]
+		switch {
+		case c == '\\' && delimiter[0] == '"':
+			if err := p.parseEscape(&builder, true); err != nil {
+				return "", err
+			}
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			builder.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated multiline string")
+}
+
+func (p *tomlParser) parseEscape(builder *strings.Builder, multiline bool) error {
+	p.pos++
+	if p.pos == len(p.text) {
+		return p.errorf("unterminated string")
+	}
+
+	c := p.text[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		builder.WriteByte('\b')
+	case 't':
+		builder.WriteByte('\t')
+	case 'n':
+		builder.WriteByte('\n')
+	case 'f':
+		builder.WriteByte('\f')
+	case 'r':
+		builder.WriteByte('\r')
+	case '"', '\\':
+		builder.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.text) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.text[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape %q", p.text[p.pos-2:p.pos+size])
+		}
+		builder.WriteRune(rune(code))
+		p.pos += size
+	case ' ', '\t', '\n':
+		p.pos--
+		rest := strings.TrimLeft(p.text[p.pos:], " \t")
+		if !multiline || !strings.HasPrefix(rest, "\n") {
+			return p.errorf("invalid escape sequence")
+		}
+		for p.pos < len(p.text) && strings.IndexByte(" \t\n", p.text[p.pos]) >= 0 {
+			if p.text[p.pos] == '\n' {
+				p.line++
+			}
+			p.pos++
+		}
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.pos++
+	array := make([]interface{}, 0)
+	for {
+		p.skipBlank(true)
+		if p.pos < len(p.text) && p.text[p.pos] == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+
+		p.skipBlank(true)
+		switch {
+		case p.pos == len(p.text):
+			return nil, p.errorf("unterminated array")
+		case p.text[p.pos] == ',':
+			p.pos++
+		case p.text[p.pos] != ']':
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]interface{}, error) {
+	p.pos++
+	table := make(map[string]interface{})
+	for {
+		p.skipBlank(false)
+		if p.pos < len(p.text) && p.text[p.pos] == '}' && len(table) == 0 {
+			p.pos++
+			return table, nil
+		}
+
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+
+		p.skipBlank(false)
+		switch {
+		case p.pos == len(p.text):
+			return nil, p.errorf("unterminated inline table")
+		case p.text[p.pos] == '}':
+			p.pos++
+			return table, nil
+		case p.text[p.pos] != ',':
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseBare() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.text) && strings.IndexByte(" \t\n,]}#", p.text[p.pos]) < 0 {
+		p.pos++
+	}
+	if isTOMLDate(p.text[start:p.pos]) && p.pos+1 < len(p.text) && p.text[p.pos] == ' ' && isDigit(p.text[p.pos+1]) {
+		p.pos++
+		for p.pos < len(p.text) && strings.IndexByte(" \t\n,]}#", p.text[p.pos]) < 0 {
+			p.pos++
+		}
+	}
+
+	token := p.text[start:p.pos]
+	switch {
+	case token == "":
+		return nil, p.errorf("expected a value")
+	case token == "true" || token == "false":
+		return token == "true", nil
+	case isTOMLDate(token) || strings.Count(token, ":") >= 2 && isDigit(token[0]):
+		return token, nil
+	}
+
+	unsigned := strings.TrimLeft(token, "+-")
+	if len(unsigned) > 1 && unsigned[0] == '0' && isDigit(unsigned[1]) {
+		return nil, p.errorf("leading zeros are not allowed in %q", token)
+	}
+	if value, err := strconv.ParseInt(token, 0, 64); err == nil {
+		return value, nil
+	}
+	if value, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64); err == nil && !strings.HasPrefix(unsigned, "0x") {
+		return value, nil
+	}
+	return nil, p.errorf("invalid value %q", token)
+}
+
+func isTOMLDate(token string) bool {
+	return len(token) >= 10 && token[4] == '-' && token[7] == '-' && isDigit(token[0]) && isDigit(token[9])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
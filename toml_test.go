@@ -0,0 +1,86 @@
+package argo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	config, err := DecodeTOML([]byte(`# server settings
+addr = "example.com" # inline comment
+port = 8_080
+ratio = 0.5
+hex = 0xff
+verbose = true
+"quoted key" = 'C:\path'
+escaped = "tab\tand \u00e9"
+date = 1979-05-27 07:32:00
+tags = [
+  "a",
+  "b", # trailing comma
+]
+labels = { team = "infra", tier = "web" }
+text = """
+one \
+  two"""
+raw = '''
+keep \n'''
+
+[network]
+host = "0.0.0.0"
+ports = [80, 443]
+tls.enabled = false
+
+[network.limits]
+rate = -1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"addr":       "example.com",
+		"port":       int64(8080),
+		"ratio":      0.5,
+		"hex":        int64(255),
+		"verbose":    true,
+		"quoted key": `C:\path`,
+		"escaped":    "tab\tand é",
+		"date":       "1979-05-27 07:32:00",
+		"tags":       []interface{}{"a", "b"},
+		"labels":     map[string]interface{}{"team": "infra", "tier": "web"},
+		"text":       "one two",
+		"raw":        `keep \n`,
+		"network": map[string]interface{}{
+			"host":   "0.0.0.0",
+			"ports":  []interface{}{int64(80), int64(443)},
+			"tls":    map[string]interface{}{"enabled": false},
+			"limits": map[string]interface{}{"rate": int64(-1)},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", expected, config)
+	}
+}
+
+func TestDecodeTOMLInvalid(t *testing.T) {
+	tests := []string{
+		"key = 1\nkey = 2\n",
+		"[a]\n[a]\n",
+		"a = 1\n[a]\n",
+		"[[servers]]\nname = \"a\"\n",
+		"key = \"unterminated\n",
+		"key = [1, 2\n",
+		"key = 01\n",
+		"key = value\n",
+		"key = 1 2\n",
+		"key\n",
+		"key = \"\\q\"\n",
+		"key = \"a \\\n b\"\n",
+	}
+	for _, test := range tests {
+		if _, err := DecodeTOML([]byte(test)); err == nil {
+			t.Fatalf("%q: expected error", test)
+		}
+	}
+}
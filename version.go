@@ -0,0 +1,50 @@
+package argo
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+const (
+	unknownVersion    string = "unknown"
+	maxRevisionLength int    = 12
+)
+
+func (p *Parser) getVersion() string {
+	if p.version != "" {
+		return p.version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return unknownVersion
+	}
+
+	version := info.Main.Version
+	if version == "" {
+		version = unknownVersion
+	}
+
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" {
+		revision = revision[:min(len(revision), maxRevisionLength)]
+		if modified {
+			revision += "-dirty"
+		}
+		version += fmt.Sprintf(" (%s)", revision)
+	}
+	return version
+}
+
+func (r *argsRegistry) printVersion() error {
+	_, err := fmt.Fprintf(r.parser.stdout, "%s %s\n", r.parser.getProgramName(), r.parser.getVersion())
+	return err
+}
@@ -0,0 +1,86 @@
+package argo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	output := &strings.Builder{}
+	parser := New(WithProgramName("tool"), WithStdout(output), WithVersion("1.2.3"))
+	if err := parser.ParseArgs(&argsSimple{}, []string{"--version"}); !errors.Is(err, ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+	if output.String() != "tool 1.2.3\n" {
+		t.Fatalf("unexpected version output '%s'", output.String())
+	}
+
+	output.Reset()
+	if err := parser.ParseArgs(&argsCommands{}, []string{"deploy", "--version"}); !errors.Is(err, ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+	if output.String() != "tool 1.2.3\n" {
+		t.Fatalf("unexpected version output '%s'", output.String())
+	}
+}
+
+type argsVersionPositional struct {
+	Name string `argo:"positional"`
+}
+
+func TestVersionPositional(t *testing.T) {
+	output := &strings.Builder{}
+	args := argsVersionPositional{}
+	parser := New(WithStdout(output), WithVersion("1.2.3"))
+	if err := parser.ParseArgs(&args, []string{"--", "--version"}); err != nil {
+		t.Fatal(err)
+	}
+	if args.Name != "--version" || output.Len() != 0 {
+		t.Fatalf("expected '--version' positional, got '%s' and output '%s'", args.Name, output.String())
+	}
+}
+
+func TestVersionBuildInfo(t *testing.T) {
+	output := &strings.Builder{}
+	parser := New(WithProgramName("tool"), WithStdout(output), WithVersion(""))
+	if err := parser.ParseArgs(&argsSimple{}, []string{"--version"}); !errors.Is(err, ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+	if !strings.HasPrefix(output.String(), "tool ") || len(strings.TrimSpace(output.String())) <= len("tool") {
+		t.Fatalf("unexpected version output '%s'", output.String())
+	}
+}
+
+func TestVersionDisabled(t *testing.T) {
+	err := ParseArgs(&argsSimple{}, []string{"--version"})
+	if !errors.Is(err, ErrUnknownArgumentName) {
+		t.Fatalf("expected ErrUnknownArgumentName, got %v", err)
+	}
+}
+
+type argsVersionField struct {
+	Version bool `argo:"long"`
+}
+
+func TestVersionCollision(t *testing.T) {
+	if err := ParseArgs(&argsVersionField{}, []string{"--version"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := New(WithVersion("1.0.0")).ParseArgs(&argsVersionField{}, []string{})
+	var parseErr *ParseError
+	if !errors.Is(err, ErrDuplicateFlagName) || !errors.As(err, &parseErr) || parseErr.Name != "--version" {
+		t.Fatalf("expected ErrDuplicateFlagName for --version, got %v", err)
+	}
+}
+
+func TestVersionHelp(t *testing.T) {
+	parser := New(WithProgramName("tool"), WithVersion("1.0.0"))
+	if help := parser.Usage(&argsSimple{}); !strings.Contains(help, "  -h, --help           Print this help message\n      --version        Print the version\n") {
+		t.Fatalf("expected version flag in help, got:\n%s", help)
+	}
+	if help := parser.Usage(&argsCommands{}); strings.Count(help, "--version") != 1 {
+		t.Fatalf("expected version flag once in help, got:\n%s", help)
+	}
+}
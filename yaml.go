@@ -0,0 +1,497 @@
+package argo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+	raw    string
+}
+
+type yamlParser struct {
+	lines []*yamlLine
+	pos   int
+}
+
+func DecodeYAML(data []byte) (map[string]interface{}, error) {
+	lines, err := splitYAMLLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	p := &yamlParser{lines: lines}
+
+	line := p.peek()
+	if line == nil {
+		return make(map[string]interface{}), nil
+	}
+	if line.indent != 0 || isYAMLSequenceItem(line.text) {
+		return nil, yamlError(line, "top level must be a mapping")
+	}
+	config, err := p.parseMapping(0)
+	if err != nil {
+		return nil, err
+	}
+	if line := p.peek(); line != nil {
+		return nil, yamlError(line, "unexpected indentation")
+	}
+	return config, nil
+}
+
+func splitYAMLLines(data string) ([]*yamlLine, error) {
+	lines := make([]*yamlLine, 0)
+	hasContent := false
+	for i, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		line := &yamlLine{number: i + 1, raw: raw}
+		trimmed := strings.TrimLeft(raw, " ")
+		line.indent = len(raw) - len(trimmed)
+		line.text = strings.TrimRight(stripYAMLComment(trimmed), " \t")
+
+		if line.indent == 0 {
+			switch {
+			case line.text == "---":
+				if hasContent {
+					return nil, yamlError(line, "multiple documents are not supported")
+				}
+				continue
+			case line.text == "...":
+				return lines, nil
+			case strings.HasPrefix(line.text, "%") && !hasContent:
+				continue
+			}
+		}
+		if line.text != "" {
+			if strings.HasPrefix(trimmed, "\t") {
+				return nil, yamlError(line, "tabs cannot be used for indentation")
+			}
+			hasContent = true
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func stripYAMLComment(text string) string {
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,:-", text[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func yamlError(line *yamlLine, msg string) error {
+	return fmt.Errorf("yaml: line %d: %s", line.number, msg)
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) peek() *yamlLine {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+	if p.pos == len(p.lines) {
+		return nil
+	}
+	return p.lines[p.pos]
+}
+
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	line := p.peek()
+	if isYAMLSequenceItem(line.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitYAMLKey(line.text); !ok {
+		return nil, yamlError(line, "expected a mapping or a sequence")
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	mapping := make(map[string]interface{})
+	for line := p.peek(); line != nil && line.indent == indent && !isYAMLSequenceItem(line.text); line = p.peek() {
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, yamlError(line, "expected a key")
+		}
+		key, err := parseYAMLKey(line, key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := mapping[key]; ok {
+			return nil, yamlError(line, fmt.Sprintf("duplicate key %q", key))
+		}
+		p.pos++
+
+		value, err := p.parseValue(line, indent, rest, true)
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+
+		if next := p.peek(); next != nil && next.indent > indent {
+			return nil, yamlError(next, "unexpected indentation")
+		}
+	}
+	return mapping, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]interface{}, error) {
+	sequence := make([]interface{}, 0)
+	for line := p.peek(); line != nil && line.indent == indent && isYAMLSequenceItem(line.text); line = p.peek() {
+		content := strings.TrimLeft(line.text[1:], " ")
+		if _, _, isMapping := splitYAMLKey(content); isMapping || isYAMLSequenceItem(content) {
+			line.indent += len(line.text) - len(content)
+			line.text = content
+			value, err := p.parseBlock(line.indent)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+
+		p.pos++
+		value, err := p.parseValue(line, indent, content, false)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+
+		if next := p.peek(); next != nil && next.indent > indent {
+			return nil, yamlError(next, "unexpected indentation")
+		}
+	}
+	return sequence, nil
+}
+
+func (p *yamlParser) parseValue(line *yamlLine, indent int, text string, inMapping bool) (interface{}, error) {
+	switch {
+	case text == "":
+		next := p.peek()
+		switch {
+		case next == nil:
+			return nil, nil
+		case next.indent > indent:
+			return p.parseBlock(next.indent)
+		case next.indent == indent && inMapping && isYAMLSequenceItem(next.text):
+			return p.parseSequence(indent)
+		default:
+			return nil, nil
+		}
+	case text[0] == '|' || text[0] == '>':
+		return p.parseBlockScalar(line, indent, text)
+	case text[0] == '[' || text[0] == '{':
+		for yamlFlowDepth(text) > 0 {
+			next := p.peek()
+			if next == nil {
+				return nil, yamlError(line, "unterminated flow collection")
+			}
+			text += " " + next.text
+			p.pos++
+		}
+		return parseYAMLFlow(line, text)
+	default:
+		return parseYAMLScalar(line, text)
+	}
+}
+
+func (p *yamlParser) parseBlockScalar(line *yamlLine, indent int, header string) (string, error) {
+	folded := header[0] == '>'
+	chomping := header[1:]
+	if chomping != "" && chomping != "-" && chomping != "+" {
+		return "", yamlError(line, fmt.Sprintf("unsupported block scalar header %q", header))
+	}
+
+	lines := make([]string, 0)
+	contentIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lineIndent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if lineIndent <= indent || (contentIndent >= 0 && lineIndent < contentIndent) {
+			break
+		}
+		if contentIndent < 0 {
+			contentIndent = lineIndent
+		}
+		lines = append(lines, raw[contentIndent:])
+	}
+
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	text := ""
+	for i, content := range lines {
+		switch {
+		case !folded && i > 0:
+			text += "\n" + content
+		case content == "":
+			text += "\n"
+		case i > 0 && lines[i-1] != "":
+			text += " " + content
+		default:
+			text += content
+		}
+	}
+
+	switch {
+	case len(lines) == 0 || chomping == "-":
+		return text, nil
+	case chomping == "+":
+		return text + "\n" + strings.Repeat("\n", trailing), nil
+	default:
+		return text + "\n", nil
+	}
+}
+
+func splitYAMLKey(text string) (string, string, bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	start := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end := yamlQuoteEnd(text)
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 1
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func parseYAMLKey(line *yamlLine, key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "? ") {
+		return "", yamlError(line, "complex keys are not supported")
+	}
+	value, err := parseYAMLScalar(line, key)
+	if err != nil {
+		return "", err
+	}
+	if value == nil {
+		return "", yamlError(line, "null keys are not supported")
+	}
+	return value.(string), nil
+}
+
+func parseYAMLScalar(line *yamlLine, text string) (interface{}, error) {
+	switch {
+	case text == "" || text == "~" || text == "null" || text == "Null" || text == "NULL":
+		return nil, nil
+	case text[0] == '&' || text[0] == '*':
+		return nil, yamlError(line, "anchors and aliases are not supported")
+	case text[0] == '!':
+		return nil, yamlError(line, "tags are not supported")
+	case text[0] == '"' || text[0] == '\'':
+		if yamlQuoteEnd(text) != len(text)-1 {
+			return nil, yamlError(line, "invalid quoted string")
+		}
+		return unquoteYAML(line, text)
+	default:
+		return text, nil
+	}
+}
+
+func yamlQuoteEnd(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteYAML(line *yamlLine, text string) (string, error) {
+	if text[0] == '\'' {
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	value, err := strconv.Unquote(text)
+	if err != nil {
+		return "", yamlError(line, "invalid escape sequence in "+text)
+	}
+	return value, nil
+}
+
+func yamlFlowDepth(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"', '\'':
+			end := yamlQuoteEnd(text[i:])
+			if end < 0 {
+				return depth
+			}
+			i += end
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+type yamlFlowParser struct {
+	line *yamlLine
+	text string
+	pos  int
+}
+
+func parseYAMLFlow(line *yamlLine, text string) (interface{}, error) {
+	p := &yamlFlowParser{line: line, text: text}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.text) {
+		return nil, yamlError(line, "unexpected characters after flow collection")
+	}
+	return value, nil
+}
+
+func (p *yamlFlowParser) skipSpaces() {
+	for p.pos < len(p.text) && (p.text[p.pos] == ' ' || p.text[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *yamlFlowParser) parseValue() (interface{}, error) {
+	p.skipSpaces()
+	if p.pos == len(p.text) {
+		return nil, yamlError(p.line, "unterminated flow collection")
+	}
+	switch p.text[p.pos] {
+	case '[':
+		return p.parseSequence()
+	case '{':
+		return p.parseMapping()
+	default:
+		return p.parseScalar(false)
+	}
+}
+
+func (p *yamlFlowParser) parseSequence() ([]interface{}, error) {
+	sequence := make([]interface{}, 0)
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.text) && p.text[p.pos] == ']' {
+			p.pos++
+			return sequence, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+		if err := p.parseSeparator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *yamlFlowParser) parseMapping() (map[string]interface{}, error) {
+	mapping := make(map[string]interface{})
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.text) && p.text[p.pos] == '}' {
+			p.pos++
+			return mapping, nil
+		}
+		key, err := p.parseScalar(true)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, yamlError(p.line, "null keys are not supported")
+		}
+		if _, ok := mapping[name]; ok {
+			return nil, yamlError(p.line, fmt.Sprintf("duplicate key %q", name))
+		}
+
+		p.skipSpaces()
+		if p.pos == len(p.text) || p.text[p.pos] != ':' {
+			return nil, yamlError(p.line, "expected ':' after key "+name)
+		}
+		p.pos++
+		if mapping[name], err = p.parseValue(); err != nil {
+			return nil, err
+		}
+		if err := p.parseSeparator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *yamlFlowParser) parseSeparator(end byte) error {
+	p.skipSpaces()
+	switch {
+	case p.pos == len(p.text):
+		return yamlError(p.line, "unterminated flow collection")
+	case p.text[p.pos] == ',':
+		p.pos++
+	case p.text[p.pos] != end:
+		return yamlError(p.line, fmt.Sprintf("expected ',' or '%c'", end))
+	}
+	return nil
+}
+
+func (p *yamlFlowParser) parseScalar(isKey bool) (interface{}, error) {
+	p.skipSpaces()
+	start := p.pos
+	if p.pos < len(p.text) && (p.text[p.pos] == '"' || p.text[p.pos] == '\'') {
+		end := yamlQuoteEnd(p.text[p.pos:])
+		if end < 0 {
+			return nil, yamlError(p.line, "unterminated quoted string")
+		}
+		p.pos += end + 1
+		return unquoteYAML(p.line, p.text[start:p.pos])
+	}
+
+	for ; p.pos < len(p.text); p.pos++ {
+		c := p.text[p.pos]
+		if c == ',' || c == ']' || c == '}' || c == '[' || c == '{' {
+			break
+		}
+		if isKey && c == ':' {
+			break
+		}
+	}
+	return parseYAMLScalar(p.line, strings.TrimSpace(p.text[start:p.pos]))
+}
@@ -0,0 +1,92 @@
+package argo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	config, err := DecodeYAML([]byte(`---
+# server settings
+addr: example.com # inline comment
+port: 8080
+"quoted key": 'it''s'
+escaped: "tab\there"
+hash: a#b
+empty:
+tilde: ~
+tags:
+- a
+- "b, c"
+flow: [1, two, "three"]
+labels: {team: infra, tier: 'web'}
+network:
+  host: 0.0.0.0
+  ports:
+    - 80
+    - 443
+  servers:
+    - name: a
+      weight: 1
+    - name: b
+script: |
+  line one
+    indented
+
+  line three
+folded: >-
+  one
+  two
+
+  three
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"addr":       "example.com",
+		"port":       "8080",
+		"quoted key": "it's",
+		"escaped":    "tab\there",
+		"hash":       "a#b",
+		"empty":      nil,
+		"tilde":      nil,
+		"tags":       []interface{}{"a", "b, c"},
+		"flow":       []interface{}{"1", "two", "three"},
+		"labels":     map[string]interface{}{"team": "infra", "tier": "web"},
+		"network": map[string]interface{}{
+			"host":  "0.0.0.0",
+			"ports": []interface{}{"80", "443"},
+			"servers": []interface{}{
+				map[string]interface{}{"name": "a", "weight": "1"},
+				map[string]interface{}{"name": "b"},
+			},
+		},
+		"script": "line one\n  indented\n\nline three\n",
+		"folded": "one two\nthree",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", expected, config)
+	}
+}
+
+func TestDecodeYAMLInvalid(t *testing.T) {
+	tests := []string{
+		"- a\n- b\n",
+		"key: value\nkey: other\n",
+		"key: value\n  nested: 1\n",
+		"\tkey: value\n",
+		"key: &anchor value\n",
+		"key: !!str value\n",
+		"key: [a, b\n",
+		"key: \"unterminated\n",
+		"just a scalar\n",
+		"a: 1\n---\nb: 2\n",
+	}
+	for _, test := range tests {
+		if _, err := DecodeYAML([]byte(test)); err == nil {
+			t.Fatalf("%q: expected error", test)
+		}
+	}
+}